@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/invidtui/cmd"
+	inv "github.com/darkhz/invidtui/invidious"
+	"github.com/darkhz/invidtui/ui/theme"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// instanceCommands lists the commands, and whether they
+// require an instance to be selected before they are run.
+var instanceCommands = map[string]bool{
+	"search":       true,
+	"video":        true,
+	"playlist":     true,
+	"channel":      true,
+	"import-theme": false,
+}
+
+// NeedsInstance returns whether the command requires an instance to be selected.
+func NeedsInstance(command string) bool {
+	return instanceCommands[command]
+}
+
+// Run runs the command provided on the command-line, and prints its
+// output as tab-separated text, or as JSON if the 'json' option is set.
+func Run(commands []string) {
+	var err error
+
+	command, args := commands[0], commands[1:]
+
+	switch command {
+	case "search":
+		err = search(args)
+
+	case "video":
+		err = video(args)
+
+	case "playlist":
+		err = playlist(args)
+
+	case "channel":
+		err = channel(args)
+
+	case "import-theme":
+		err = importTheme(args)
+
+	default:
+		err = fmt.Errorf("Unknown command %q", command)
+	}
+
+	if err != nil {
+		cmd.PrintError("Command", err)
+	}
+}
+
+// search searches for videos, playlists or channels.
+func search(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("Usage: search <video|playlist|channel> <query>")
+	}
+
+	stype, query := args[0], strings.Join(args[1:], " ")
+	if stype != "video" && stype != "playlist" && stype != "channel" {
+		return fmt.Errorf("Invalid search type %q", stype)
+	}
+
+	results, _, err := inv.Search(stype, query, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	return output(results, func(lines *[]string) {
+		for _, result := range results {
+			var line []string
+
+			switch result.Type {
+			case "video":
+				line = []string{
+					result.VideoID, result.Title, result.Author,
+					utils.FormatDuration(result.LengthSeconds),
+				}
+
+			case "playlist":
+				line = []string{
+					result.PlaylistID, result.Title, result.Author,
+					strconv.FormatInt(result.VideoCount, 10) + " videos",
+				}
+
+			case "channel":
+				line = []string{
+					result.AuthorID, result.Author,
+					utils.FormatNumber(result.SubCount) + " subscribers",
+				}
+
+			default:
+				continue
+			}
+
+			*lines = append(*lines, strings.Join(line, "\t"))
+		}
+	})
+}
+
+// video shows information about a video.
+func video(args []string) error {
+	id, err := mediaID(args, "video")
+	if err != nil {
+		return err
+	}
+
+	video, err := inv.Video(id)
+	if err != nil {
+		return err
+	}
+
+	return output(video, func(lines *[]string) {
+		*lines = append(*lines,
+			"Title: "+video.Title,
+			"Author: "+video.Author,
+			"ID: "+video.VideoID,
+			"Duration: "+utils.FormatDuration(video.LengthSeconds),
+			"Views: "+utils.FormatNumber(video.ViewCount),
+			"Likes: "+utils.FormatNumber(video.LikeCount),
+			"Published: "+video.PublishedText,
+		)
+		if video.Description != "" {
+			*lines = append(*lines, "", video.Description)
+		}
+	})
+}
+
+// playlist shows the videos in a playlist.
+func playlist(args []string) error {
+	id, err := mediaID(args, "playlist")
+	if err != nil {
+		return err
+	}
+
+	playlist, videos, err := inv.PlaylistVideos(context.Background(), id, false, func(stats [3]int64) {})
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		inv.PlaylistData
+		Videos []inv.VideoData `json:"videos"`
+	}{
+		PlaylistData: playlist,
+		Videos:       videos,
+	}
+
+	return output(data, func(lines *[]string) {
+		for _, video := range videos {
+			*lines = append(*lines, strings.Join([]string{
+				video.VideoID, video.Title, video.Author,
+				utils.FormatDuration(video.LengthSeconds),
+			}, "\t"))
+		}
+	})
+}
+
+// channel shows the videos or playlists of a channel.
+func channel(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Usage: channel <id> [videos|playlists]")
+	}
+
+	id, stype := args[0], "videos"
+	if len(args) > 1 {
+		stype = args[1]
+	}
+	if stype != "videos" && stype != "playlists" {
+		return fmt.Errorf("Invalid channel type %q", stype)
+	}
+
+	channel, err := inv.Channel(id, stype, "")
+	if err != nil {
+		return err
+	}
+
+	return output(channel, func(lines *[]string) {
+		for _, video := range channel.Videos {
+			*lines = append(*lines, strings.Join([]string{
+				video.VideoID, video.Title, video.Author,
+				utils.FormatDuration(video.LengthSeconds),
+			}, "\t"))
+		}
+
+		for _, playlist := range channel.Playlists {
+			*lines = append(*lines, strings.Join([]string{
+				playlist.PlaylistID, playlist.Title,
+				strconv.FormatInt(playlist.VideoCount, 10) + " videos",
+			}, "\t"))
+		}
+	})
+}
+
+// importTheme converts a color scheme into a theme.
+func importTheme(args []string) error {
+	var name string
+
+	if len(args) == 0 {
+		return fmt.Errorf("Usage: import-theme <file> [name]")
+	}
+	if len(args) > 1 {
+		name = args[1]
+	}
+
+	themePath, err := theme.ImportScheme(args[0], name, false)
+	if errors.Is(err, fs.ErrExist) {
+		if !cmd.Confirm("Theme " + themePath + " already exists, overwrite?") {
+			return err
+		}
+
+		themePath, err = theme.ImportScheme(args[0], name, true)
+	}
+	if err != nil {
+		return err
+	}
+
+	cmd.PrintResult("Theme saved to " + themePath)
+
+	return nil
+}
+
+// output prints the data as JSON if the 'json' option is set,
+// otherwise it prints the lines generated by the text function.
+func output(data interface{}, text func(lines *[]string)) error {
+	if cmd.IsOptionEnabled("json") {
+		out, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		cmd.PrintResult(string(out))
+
+		return nil
+	}
+
+	var lines []string
+
+	text(&lines)
+	cmd.PrintResult(strings.Join(lines, "\n"))
+
+	return nil
+}
+
+// mediaID returns the video or playlist ID from the first argument,
+// which can either be an ID or a URL.
+func mediaID(args []string, mtype string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("Usage: %s <id|url>", mtype)
+	}
+	if !strings.ContainsAny(args[0], "/?") {
+		return args[0], nil
+	}
+
+	id, idType, _, err := utils.GetVPIDFromURL(args[0])
+	if err != nil {
+		return "", err
+	}
+	if idType != mtype {
+		return "", fmt.Errorf("%s is not a %s", args[0], mtype)
+	}
+
+	return id, nil
+}
@@ -126,6 +126,28 @@ func Patch(ctx context.Context, param, body string, token ...string) (*http.Resp
 	return res, err
 }
 
+// GetURL sends a GET request to the provided URL, which need not
+// be on the host, and returns a response.
+func GetURL(ctx context.Context, uri string) (*http.Response, error) {
+	if client.Client == nil {
+		return nil, fmt.Errorf("Client: Not initialized")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", UserAgent)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, netError(err)
+	}
+
+	return checkStatusCode(res, http.StatusOK)
+}
+
 // Fetch sends a GET request to the API endpoint and returns a response.
 func Fetch(ctx context.Context, param string, token ...string) (*http.Response, error) {
 	return Get(ctx, API+param, token...)
@@ -0,0 +1,98 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// attach attaches the terminal to a running daemon, if the 'attach'
+// command-line parameter is set, and exits once it is detached.
+func attach() {
+	if !IsOptionEnabled("attach") {
+		return
+	}
+
+	var mutex sync.Mutex
+
+	conn, err := net.Dial("unix", filepath.Join(config.path, DaemonSocket))
+	if err != nil {
+		printer.Error(fmt.Sprintf("Cannot connect to the daemon, is it running? (%s)", err))
+	}
+	defer conn.Close()
+
+	tty, err := tcell.NewDevTty()
+	if err != nil {
+		printer.Error(fmt.Sprintf("Cannot open terminal: %s", err))
+	}
+
+	size, err := tty.WindowSize()
+	if err != nil {
+		printer.Error(fmt.Sprintf("Cannot get terminal size: %s", err))
+	}
+
+	header, err := json.Marshal(AttachHeader{
+		Term:   os.Getenv("TERM"),
+		Width:  size.Width,
+		Height: size.Height,
+	})
+	if err != nil {
+		printer.Error(err.Error())
+	}
+
+	printer.Stop()
+
+	if _, err := conn.Write(append(header, '\n')); err != nil {
+		printer.Error(fmt.Sprintf("Cannot attach to the daemon: %s", err))
+	}
+
+	if err := tty.Start(); err != nil {
+		printer.Error(fmt.Sprintf("Cannot start terminal: %s", err))
+	}
+
+	tty.NotifyResize(func() {
+		size, err := tty.WindowSize()
+		if err != nil {
+			return
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		WriteAttachFrame(conn, AttachFrameResize, ResizePayload(size.Width, size.Height))
+	})
+
+	go func() {
+		buf := make([]byte, 4096)
+
+		for {
+			n, err := tty.Read(buf)
+			if n > 0 {
+				mutex.Lock()
+				err = WriteAttachFrame(conn, AttachFrameInput, buf[:n])
+				mutex.Unlock()
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	io.Copy(tty, conn)
+
+	tty.NotifyResize(nil)
+	tty.Drain()
+	tty.Stop()
+	tty.Close()
+
+	printer.Print("Detached from the daemon", 0)
+}
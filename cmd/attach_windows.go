@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package cmd
+
+// attach checks the 'attach' and 'daemon' command-line parameters,
+// which are not supported on Windows.
+func attach() {
+	if IsOptionEnabled("attach") || IsOptionEnabled("daemon") {
+		printer.Error("Daemon mode is not supported on Windows")
+	}
+}
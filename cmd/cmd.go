@@ -9,8 +9,12 @@ import (
 	mp "github.com/darkhz/invidtui/mediaplayer"
 )
 
-// Version stores the version information.
-var Version string
+var (
+	// Version stores the version information.
+	Version string
+
+	needsInstance func(command string) bool
+)
 
 // Init parses the command-line parameters and initializes the application.
 func Init() {
@@ -18,6 +22,7 @@ func Init() {
 	config.setup()
 
 	parse()
+	attach()
 
 	printVersion()
 	generate()
@@ -27,12 +32,28 @@ func Init() {
 
 	check()
 
-	loadInstance()
-	loadPlayer()
+	if commands == nil || needsInstance == nil || needsInstance(commands[0]) {
+		loadInstance()
+	}
+	if commands == nil {
+		loadPlayer()
+	}
 
 	printer.Stop()
 }
 
+// SetInstanceFunc sets the function which determines whether
+// the command provided on the command-line requires an instance.
+func SetInstanceFunc(instanceFunc func(command string) bool) {
+	needsInstance = instanceFunc
+}
+
+// Commands returns the command and its arguments, if
+// one was provided on the command-line.
+func Commands() []string {
+	return commands
+}
+
 // loadInstance selects an instance.
 func loadInstance() {
 	if IsOptionEnabled("instance-validated") {
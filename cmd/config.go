@@ -17,6 +17,8 @@ import (
 type Config struct {
 	path, socket string
 
+	file *koanf.Koanf
+
 	mutex sync.Mutex
 
 	*koanf.Koanf
@@ -162,6 +164,22 @@ func generateConfig() {
 		for _, name := range []string{
 			"force-instance",
 			"download-dir",
+			"download-limit",
+			"download-schedule",
+			"download-hook",
+			"download-sidecars",
+			"remote-control",
+			"mpris",
+			"mouse",
+			"compact-player",
+			"player-position",
+			"side-pane",
+			"side-pane-width",
+			"small-terminal-height",
+			"mpd-address",
+			"now-playing-file",
+			"now-playing-format",
+			"now-playing-socket",
 			"num-retries",
 			"video-res",
 		} {
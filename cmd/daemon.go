@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+)
+
+// AttachHeader describes the terminal properties which are sent
+// by a client when it attaches to the daemon.
+type AttachHeader struct {
+	Term   string `json:"term"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// DaemonSocket is the name of the daemon's socket within the config directory.
+const DaemonSocket = "daemon.sock"
+
+// The types of frames sent by an attached client to the daemon.
+// Each frame consists of its type, the length of its payload as
+// a 16-bit big-endian integer, and its payload.
+const (
+	AttachFrameInput  byte = 'i'
+	AttachFrameResize byte = 'r'
+)
+
+// WriteAttachFrame writes a frame to the writer.
+func WriteAttachFrame(w io.Writer, frameType byte, payload []byte) error {
+	frame := make([]byte, 3, len(payload)+3)
+	frame[0] = frameType
+	binary.BigEndian.PutUint16(frame[1:], uint16(len(payload)))
+
+	_, err := w.Write(append(frame, payload...))
+
+	return err
+}
+
+// ReadAttachFrame reads a frame from the reader.
+func ReadAttachFrame(r *bufio.Reader) (byte, []byte, error) {
+	header := make([]byte, 3)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[1:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return header[0], payload, nil
+}
+
+// ResizePayload returns the payload of a resize frame.
+func ResizePayload(width, height int) []byte {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint16(payload, uint16(width))
+	binary.BigEndian.PutUint16(payload[2:], uint16(height))
+
+	return payload
+}
+
+// ParseResizePayload returns the width and height from the payload of a resize frame.
+func ParseResizePayload(payload []byte) (int, int, bool) {
+	if len(payload) != 4 {
+		return 0, 0, false
+	}
+
+	return int(binary.BigEndian.Uint16(payload)), int(binary.BigEndian.Uint16(payload[2:])), true
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,6 +19,7 @@ import (
 	"github.com/knadh/koanf/parsers/hjson"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/posflag"
+	"github.com/knadh/koanf/v2"
 )
 
 // Option describes a command-line option.
@@ -26,6 +28,8 @@ type Option struct {
 	Value, Type       string
 }
 
+var commands []string
+
 var options = []Option{
 	{
 		Name:        "token",
@@ -99,6 +103,66 @@ var options = []Option{
 		Value:       "100",
 		Type:        "other",
 	},
+	{
+		Name:        "download-limit",
+		Description: "Set the global download rate limit (for example, 500K or 2M bytes/sec).",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "download-schedule",
+		Description: "Set the daily time window to run automatic downloads in (for example, 01:00-06:00).",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "download-hook",
+		Description: "Specify a command to run after a download completes.\nThis is used as the 'on-download-complete' hook, if the hook is not configured,\nand is stopped if it does not complete within the hooks' 'download-timeout' (10m by default).",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "mpd-address",
+		Description: "Specify an address (for example, localhost:6600) to serve the MPD protocol on.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "now-playing-file",
+		Description: "Specify a file to write the now-playing status to, whenever it changes.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "now-playing-format",
+		Description: "Set the format of the now-playing status, either 'json' or a template\n(for example, '{{.Title}} - {{.Author}} [{{duration .Position}}/{{duration .Duration}}]').",
+		Value:       "json",
+		Type:        "other",
+	},
+	{
+		Name:        "player-position",
+		Description: "Set the position of the player, either 'top' or 'bottom'.",
+		Value:       "bottom",
+		Type:        "other",
+	},
+	{
+		Name:        "side-pane",
+		Description: "Show the player's 'queue' or 'info' in a side pane alongside the current view.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "side-pane-width",
+		Description: "Set the width of the side pane, as a percentage of the screen width.",
+		Value:       "33",
+		Type:        "other",
+	},
+	{
+		Name:        "small-terminal-height",
+		Description: "Set the screen height (in rows) below which the player is displayed in a single line.",
+		Value:       "20",
+		Type:        "other",
+	},
 	{
 		Name:        "force-instance",
 		Description: "Force load media from specified invidious instance.",
@@ -111,6 +175,60 @@ var options = []Option{
 		Value:       "",
 		Type:        "other",
 	},
+	{
+		Name:        "download-sidecars",
+		Description: "Save the thumbnail, description and video information alongside downloaded files.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "remote-control",
+		Description: "Enable the remote-control socket (control.sock) within the config directory.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "now-playing-socket",
+		Description: "Serve the now-playing status on a socket (nowplaying.sock) within the config directory.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "mouse",
+		Description: "Enable mouse support.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "compact-player",
+		Description: "Display the player in a single line.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "mpris",
+		Description: "Enable the MPRIS D-Bus interface (Linux only).",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "daemon",
+		Description: "Run in the background without a UI. Send SIGINT or SIGTERM to stop the daemon.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "attach",
+		Description: "Attach the UI to a running daemon. Quitting the UI detaches it from the daemon.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "json",
+		Description: "Print the output of a command as JSON.",
+		Value:       "",
+		Type:        "bool",
+	},
 	{
 		Name:        "close-instances",
 		Description: "Close all currently running instances.",
@@ -155,9 +273,16 @@ func parse() {
 		var usage string
 
 		usage += fmt.Sprintf(
-			"invidtui [<flags>]\n\nConfig file is %s\n\nFlags:\n",
+			"invidtui [<flags>]\ninvidtui [<flags>] <command> [<args>]\n\nConfig file is %s\n\n",
 			configFile,
 		)
+		usage += "Commands:\n" +
+			"  search <video|playlist|channel> <query>\n    \tSearch for videos, playlists or channels.\n" +
+			"  video <id|url>\n    \tShow information about a video.\n" +
+			"  playlist <id|url>\n    \tShow the videos in a playlist.\n" +
+			"  channel <id> [videos|playlists]\n    \tShow the videos (default) or playlists of a channel.\n" +
+			"  import-theme <file> [name]\n    \tConvert a base16, alacritty, kitty or Xresources color scheme into a theme.\n\n" +
+			"Flags:\n"
 
 		fs.VisitAll(func(f *flag.Flag) {
 			s := fmt.Sprintf("  --%s", f.Name)
@@ -181,17 +306,28 @@ func parse() {
 				"force-instance",
 				"close-instances",
 				"version",
+				"daemon",
+				"attach",
+				"json",
 				"download-dir",
+				"download-limit",
+				"download-schedule",
+				"download-hook",
+				"mpd-address",
+				"now-playing-file",
+				"side-pane",
 			} {
 				if f.Name == name {
 					goto cmdOutPrint
 				}
 			}
 
-			if f.Name != "num-retries" {
-				s += fmt.Sprintf(" (default %q)", f.DefValue)
-			} else {
+			switch f.Name {
+			case "num-retries", "side-pane-width", "small-terminal-height":
 				s += fmt.Sprintf(" (default %v)", f.DefValue)
+
+			default:
+				s += fmt.Sprintf(" (default %q)", f.DefValue)
 			}
 
 		cmdOutPrint:
@@ -215,7 +351,15 @@ func parse() {
 		printer.Error(err.Error())
 	}
 
-	if err := config.Load(file.Provider(configFile), hjson.Parser()); err != nil {
+	if fs.NArg() > 0 {
+		commands = fs.Args()
+	}
+
+	config.file = koanf.New(".")
+	if err := config.file.Load(file.Provider(configFile), hjson.Parser()); err != nil {
+		printer.Error(err.Error())
+	}
+	if err := config.Merge(config.file); err != nil {
 		printer.Error(err.Error())
 	}
 
@@ -229,13 +373,17 @@ func check() {
 	RunAllParsers()
 	getSettings()
 
-	checkSocket()
+	if commands == nil {
+		checkSocket()
+	}
 	checkAuth()
 
 	for _, option := range options {
 		switch option.Type {
 		case "path":
-			checkExecutablePaths(option.Name, GetOptionValue(option.Name))
+			if commands == nil {
+				checkExecutablePaths(option.Name, GetOptionValue(option.Name))
+			}
 
 		case "other":
 			checkOtherOptions(option.Name, GetOptionValue(option.Name))
@@ -362,6 +510,41 @@ func checkOtherOptions(otherType, other string) {
 			printer.Error("Invalid value for num-retries")
 		}
 
+	case "download-limit":
+		if _, err := utils.ParseByteRate(other); err != nil {
+			printer.Error("Invalid value for download-limit")
+		}
+
+	case "mpd-address":
+		if _, _, err := net.SplitHostPort(other); err != nil {
+			printer.Error("Invalid value for mpd-address: " + err.Error())
+		}
+
+	case "download-schedule":
+		if _, _, err := utils.ParseTimeWindow(other); err != nil {
+			printer.Error("Invalid value for download-schedule: " + err.Error())
+		}
+
+	case "player-position":
+		if other != "top" && other != "bottom" {
+			printer.Error("Invalid value for player-position")
+		}
+
+	case "side-pane":
+		if other != "queue" && other != "info" {
+			printer.Error("Invalid value for side-pane")
+		}
+
+	case "side-pane-width":
+		if width, err := strconv.Atoi(other); err != nil || width < 10 || width > 90 {
+			printer.Error("Invalid value for side-pane-width, must be between 10 and 90")
+		}
+
+	case "small-terminal-height":
+		if _, err := strconv.Atoi(other); err != nil {
+			printer.Error("Invalid value for small-terminal-height")
+		}
+
 	case "video-res":
 		for _, res := range []string{
 			"144p",
@@ -1,6 +1,13 @@
 package cmd
 
-import "github.com/knadh/koanf/v2"
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/knadh/koanf/parsers/hjson"
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
+)
 
 // ConfigHandler describes a configuration handler.
 type ConfigHandler interface {
@@ -18,8 +25,12 @@ type ConfigType string
 
 // The different types of configuration handlers.
 const (
-	ConfigTheme       ConfigType = "theme"
-	ConfigKeybindings ConfigType = "keybindings"
+	ConfigTheme        ConfigType = "theme"
+	ConfigKeybindings  ConfigType = "keybindings"
+	ConfigAutoDownload ConfigType = "autodownload"
+	ConfigHooks        ConfigType = "hooks"
+	ConfigScrobble     ConfigType = "scrobble"
+	ConfigNotify       ConfigType = "notifications"
 )
 
 var handler ConfigSettings
@@ -42,6 +53,53 @@ func RunAllParsers() {
 	}
 }
 
+// ReloadConfig reloads the configuration file, and runs the parsers of the
+// provided configuration types whose values have changed within the file.
+func ReloadConfig(types ...ConfigType) error {
+	conf, err := GetPath("invidtui.conf")
+	if err != nil {
+		return err
+	}
+
+	k := koanf.New(".")
+	if err := k.Load(file.Provider(conf), hjson.Parser()); err != nil {
+		return fmt.Errorf("Config: Cannot load configuration: %w", err)
+	}
+
+	for _, configType := range types {
+		h, ok := handler.settings[configType]
+		if !ok {
+			continue
+		}
+
+		name := string(configType)
+
+		config.mutex.Lock()
+		changed := config.file == nil || !reflect.DeepEqual(config.file.Get(name), k.Get(name))
+		if changed {
+			config.Delete(name)
+			if k.Exists(name) {
+				config.Set(name, k.Get(name))
+			}
+		}
+		config.mutex.Unlock()
+
+		if !changed {
+			continue
+		}
+
+		if err := h.Parse(config.Koanf, config.path); err != nil {
+			return err
+		}
+	}
+
+	config.mutex.Lock()
+	config.file = k
+	config.mutex.Unlock()
+
+	return nil
+}
+
 // RunAllGenerators runs all the stored handler's generators.
 func RunAllGenerators(genMap map[string]interface{}) {
 	for i, h := range handler.settings {
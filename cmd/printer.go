@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/theckman/yacspin"
 )
 
@@ -15,10 +18,18 @@ type Printer struct {
 
 var printer Printer
 
-// setup sets up the printer.
+// setup sets up the printer. If the standard output is not a terminal,
+// the spinner is displayed on the standard error instead, so that the
+// output of commands can be piped without the spinner's messages.
 func (p *Printer) setup() {
+	writer := os.Stdout
+	if !isatty.IsTerminal(os.Stdout.Fd()) && !isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+		writer = os.Stderr
+	}
+
 	spinner, err := yacspin.New(
 		yacspin.Config{
+			Writer:            writer,
 			Frequency:         100 * time.Millisecond,
 			CharSet:           yacspin.CharSets[59],
 			Message:           "Loading",
@@ -68,6 +79,33 @@ func (p *Printer) Error(message string) {
 	os.Exit(1)
 }
 
+// PrintResult stops the spinner and prints the result of a command to the standard output.
+func PrintResult(result string) {
+	printer.Stop()
+
+	fmt.Fprintln(os.Stdout, result)
+}
+
+// Confirm asks for a confirmation on the terminal, and returns whether
+// it was given. If the standard input is not a terminal, false is returned.
+func Confirm(prompt string) bool {
+	if !isatty.IsTerminal(os.Stdin.Fd()) && !isatty.IsCygwinTerminal(os.Stdin.Fd()) {
+		return false
+	}
+
+	printer.Stop()
+	fmt.Fprint(os.Stderr, prompt+" (y/n) ")
+
+	reply, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	reply = strings.ToLower(strings.TrimSpace(reply))
+
+	return reply == "y" || reply == "yes"
+}
+
 // PrintError prints an error to the screen.
 func PrintError(message string, err ...error) {
 	if err != nil {
@@ -20,7 +20,8 @@ type SettingsData struct {
 	SearchHistory []string              `json:"searchHistory"`
 	PlayHistory   []PlayHistorySettings `json:"playHistory"`
 
-	PlayerStates []string `json:"playerStates"`
+	PlayerStates   []string `json:"playerStates"`
+	RecentCommands []string `json:"recentCommands"`
 }
 
 // PlayHistorySettings describes the format to store the play history.
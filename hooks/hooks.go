@@ -0,0 +1,163 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/platform"
+	"github.com/knadh/koanf/v2"
+)
+
+// Hooks describes the event hooks configuration.
+type Hooks struct {
+	timeout, downloadTimeout time.Duration
+	commands                 map[Event]string
+
+	errorHandler func(err error)
+
+	mutex sync.Mutex
+}
+
+// Event describes a hook event.
+type Event string
+
+// The different hook events.
+const (
+	EventStart            Event = "on-start"
+	EventEnd              Event = "on-end"
+	EventPause            Event = "on-pause"
+	EventQueueEmpty       Event = "on-queue-empty"
+	EventDownloadComplete Event = "on-download-complete"
+)
+
+// The default durations after which a hook command is stopped. Download
+// completion hooks are given longer, since they usually move or process files.
+const (
+	DefaultTimeout         = 30 * time.Second
+	DefaultDownloadTimeout = 10 * time.Minute
+)
+
+var hooks Hooks
+
+// GetConfigHandler returns the hooks configuration handler.
+func GetConfigHandler() *Hooks {
+	return &hooks
+}
+
+// Parse parses the hook commands from the configuration.
+func (h *Hooks) Parse(k *koanf.Koanf, dir string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.timeout, h.downloadTimeout = DefaultTimeout, DefaultDownloadTimeout
+	h.commands = make(map[Event]string)
+
+	// The 'download-hook' option is run as the download completion hook,
+	// unless the hook is configured separately.
+	if command := strings.TrimSpace(k.String("download-hook")); command != "" {
+		h.commands[EventDownloadComplete] = command
+	}
+
+	if !k.Exists("hooks") {
+		return nil
+	}
+
+	for key, value := range map[string]*time.Duration{
+		"timeout":          &h.timeout,
+		"download-timeout": &h.downloadTimeout,
+	} {
+		timeout := k.String("hooks." + key)
+		if timeout == "" {
+			continue
+		}
+
+		duration, err := time.ParseDuration(timeout)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("Config: Invalid hook %s %q", key, timeout)
+		}
+
+		*value = duration
+	}
+
+	for _, event := range []Event{
+		EventStart,
+		EventEnd,
+		EventPause,
+		EventQueueEmpty,
+		EventDownloadComplete,
+	} {
+		if command := strings.TrimSpace(k.String("hooks." + string(event))); command != "" {
+			h.commands[event] = command
+		}
+	}
+
+	return nil
+}
+
+// Generate generates the hooks configuration.
+func (h *Hooks) Generate(k *koanf.Koanf) (interface{}, error) {
+	hooksMap := k.Get("hooks")
+	if hooksMap == nil {
+		hooksMap = map[string]interface{}{
+			"timeout":                     DefaultTimeout.String(),
+			"download-timeout":            DefaultDownloadTimeout.String(),
+			string(EventStart):            "",
+			string(EventEnd):              "",
+			string(EventPause):            "",
+			string(EventQueueEmpty):       "",
+			string(EventDownloadComplete): "",
+		}
+	}
+
+	return hooksMap, nil
+}
+
+// SetErrorHandler sets the function which is called when a hook command fails.
+func SetErrorHandler(handler func(err error)) {
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+
+	hooks.errorHandler = handler
+}
+
+// Run runs the command configured for the event in the background, with the
+// event name and the provided environment variables. The command is stopped
+// if it does not complete within the configured timeout, or the configured
+// download timeout for download completion hooks.
+func Run(event Event, env []string) {
+	hooks.mutex.Lock()
+	command, ok := hooks.commands[event]
+	timeout, handler := hooks.timeout, hooks.errorHandler
+	if event == EventDownloadComplete {
+		timeout = hooks.downloadTimeout
+	}
+	hooks.mutex.Unlock()
+
+	if !ok {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		cmd := platform.ShellCommand(ctx, command)
+		cmd.Env = append(os.Environ(), "INVIDTUI_EVENT="+string(event))
+		cmd.Env = append(cmd.Env, env...)
+
+		err := cmd.Run()
+		if err == nil || handler == nil {
+			return
+		}
+
+		if ctx.Err() != nil {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+
+		handler(fmt.Errorf("Hooks: %s command failed: %w", event, err))
+	}()
+}
@@ -1,6 +1,8 @@
 package invidious
 
 import (
+	"context"
+
 	"github.com/darkhz/invidtui/client"
 	"github.com/darkhz/invidtui/resolver"
 )
@@ -33,7 +35,7 @@ func Channel(id, stype, continuation string, channel ...ChannelData) (ChannelDat
 	query = "channels/" + id
 
 	// Get the channel data first.
-	data, err = decodeChannelData(query)
+	data, err = decodeChannelData(client.Ctx(), query)
 	if err != nil {
 		return ChannelData{}, err
 	}
@@ -45,7 +47,7 @@ GetData:
 		query += "?continuation=" + continuation
 	}
 
-	d, err := decodeChannelData(query)
+	d, err := decodeChannelData(client.Ctx(), query)
 	if err != nil {
 		return ChannelData{}, err
 	}
@@ -62,6 +64,17 @@ func ChannelVideos(id, continuation string) (ChannelData, error) {
 	return Channel(id, "videos", continuation)
 }
 
+// ChannelUploads retrieves the latest videos uploaded to a channel.
+// Unlike ChannelVideos, it does not cancel any ongoing requests.
+func ChannelUploads(ctx context.Context, id string) ([]PlaylistVideo, error) {
+	data, err := decodeChannelData(ctx, "channels/"+id+"/videos")
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Videos, nil
+}
+
 // ChannelPlaylists loads only the playlists present in the channel.
 func ChannelPlaylists(id, continuation string) (ChannelData, error) {
 	return Channel(id, "playlists", continuation)
@@ -78,10 +91,10 @@ func ChannelSearch(id, searchText string, page int) ([]SearchData, int, error) {
 }
 
 // decodeChannelData sends a channel query, parses and returns the response.
-func decodeChannelData(query string) (ChannelData, error) {
+func decodeChannelData(ctx context.Context, query string) (ChannelData, error) {
 	var data ChannelData
 
-	res, err := client.Fetch(client.Ctx(), query)
+	res, err := client.Fetch(ctx, query)
 	if err != nil {
 		return ChannelData{}, err
 	}
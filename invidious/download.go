@@ -2,16 +2,61 @@ package invidious
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/darkhz/invidtui/client"
 	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/resolver"
 )
 
+// DownloadMetadata describes the metadata of a downloaded file,
+// which is stored alongside it.
+type DownloadMetadata struct {
+	VideoID    string `json:"videoId"`
+	Title      string `json:"title"`
+	Author     string `json:"author"`
+	Itag       string `json:"itag"`
+	MediaType  string `json:"mediaType"`
+	Container  string `json:"container"`
+	File       string `json:"file"`
+	Size       int64  `json:"size"`
+	Downloaded int64  `json:"downloaded"`
+}
+
+// downloadIndex describes the index of downloaded media,
+// keyed by the video ID.
+type downloadIndex struct {
+	dir   string
+	store map[string][]DownloadMetadata
+
+	mutex sync.Mutex
+}
+
+// The media types of a downloaded file.
+const (
+	DownloadMediaAudio      = "audio"
+	DownloadMediaVideo      = "video"
+	DownloadMediaAudioVideo = "audiovideo"
+)
+
+// DownloadMetadataSuffix is the suffix of the metadata file
+// stored alongside a downloaded file.
+const DownloadMetadataSuffix = ".meta.json"
+
+var index downloadIndex
+
 // DownloadParams returns parameters that are used to download a file.
 func DownloadParams(ctx context.Context, id, itag, filename string) (*http.Response, *os.File, error) {
 	dir := cmd.GetOptionValue("download-dir")
@@ -26,10 +71,287 @@ func DownloadParams(ctx context.Context, id, itag, filename string) (*http.Respo
 		return nil, nil, err
 	}
 
-	file, err := os.OpenFile(filepath.Join(dir, filename), os.O_CREATE|os.O_WRONLY, 0644)
+	file, err := os.OpenFile(filepath.Join(dir, filename), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	return res, file, err
 }
+
+// VerifyDownload checks whether the downloaded file is complete, by comparing
+// the amount of bytes written with the expected size, and by probing the file
+// with ffprobe or ffmpeg, if either of them are available.
+func VerifyDownload(ctx context.Context, file string, written, expected int64) error {
+	if expected > 0 && written != expected {
+		return fmt.Errorf("Download: Incomplete file (%d of %d bytes)", written, expected)
+	}
+
+	var command *exec.Cmd
+
+	ffmpeg := cmd.GetOptionValue("ffmpeg-path")
+	ffprobe := filepath.Join(
+		filepath.Dir(ffmpeg),
+		strings.Replace(filepath.Base(ffmpeg), "ffmpeg", "ffprobe", 1),
+	)
+
+	if path, err := exec.LookPath(ffprobe); err == nil {
+		command = exec.CommandContext(ctx, path, "-v", "error", "-i", file)
+	} else if path, err := exec.LookPath(ffmpeg); err == nil {
+		command = exec.CommandContext(ctx, path, "-v", "error", "-i", file, "-map", "0", "-c", "copy", "-f", "null", "-")
+	} else {
+		return nil
+	}
+
+	output, err := command.CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return fmt.Errorf("Download: Corrupted file: %s", commandError(output, err))
+	}
+
+	return nil
+}
+
+// Environ returns the metadata as a list of environment variables.
+func (d DownloadMetadata) Environ() []string {
+	return []string{
+		"INVIDTUI_FILE=" + d.File,
+		"INVIDTUI_VIDEO_ID=" + d.VideoID,
+		"INVIDTUI_TITLE=" + d.Title,
+		"INVIDTUI_AUTHOR=" + d.Author,
+		"INVIDTUI_ITAG=" + d.Itag,
+		"INVIDTUI_MEDIA_TYPE=" + d.MediaType,
+		"INVIDTUI_CONTAINER=" + d.Container,
+		"INVIDTUI_SIZE=" + strconv.FormatInt(d.Size, 10),
+	}
+}
+
+// WriteDownloadMetadata writes the metadata of a downloaded file alongside it,
+// and adds it to the download index.
+func WriteDownloadMetadata(metadata DownloadMetadata) error {
+	data, err := json.MarshalIndent(metadata, "", " ")
+	if err != nil {
+		return fmt.Errorf("Download: Cannot encode metadata: %w", err)
+	}
+
+	if err := os.WriteFile(metadata.File+DownloadMetadataSuffix, data, 0644); err != nil {
+		return fmt.Errorf("Download: Cannot write metadata: %w", err)
+	}
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if index.load() {
+		index.add(metadata)
+	}
+
+	return nil
+}
+
+// WriteDownloadSidecars saves the thumbnail, description and information of
+// the video alongside the downloaded file, as '<file>.jpg', '<file>.description'
+// and '<file>.info.json' respectively. The sidecars are named after the full
+// file name, since the audio and video files of a video can share a base name.
+func WriteDownloadSidecars(ctx context.Context, file string, video VideoData) error {
+	if err := os.WriteFile(file+".description", []byte(video.Description), 0644); err != nil {
+		return fmt.Errorf("Download: Cannot write description: %w", err)
+	}
+
+	info, err := json.MarshalIndent(video, "", " ")
+	if err != nil {
+		return fmt.Errorf("Download: Cannot encode video information: %w", err)
+	}
+	if err := os.WriteFile(file+".info.json", info, 0644); err != nil {
+		return fmt.Errorf("Download: Cannot write video information: %w", err)
+	}
+
+	image := "hqdefault.jpg"
+	width := 0
+	for _, thumbnail := range video.Thumbnails {
+		name := filepath.Base(thumbnail.URL)
+		if thumbnail.Width > width && filepath.Ext(name) == ".jpg" {
+			image, width = name, thumbnail.Width
+		}
+	}
+
+	res, err := VideoThumbnail(ctx, video.VideoID, image)
+	if err != nil {
+		return fmt.Errorf("Download: Cannot fetch thumbnail: %w", err)
+	}
+	defer res.Body.Close()
+
+	thumbnail, err := os.OpenFile(file+".jpg", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("Download: Cannot create thumbnail: %w", err)
+	}
+	defer thumbnail.Close()
+
+	if _, err := io.Copy(thumbnail, res.Body); err != nil {
+		return fmt.Errorf("Download: Cannot save thumbnail: %w", err)
+	}
+
+	return nil
+}
+
+// ReadDownloadInfo reads the video information saved alongside the downloaded file.
+func ReadDownloadInfo(file string) (VideoData, error) {
+	var video VideoData
+
+	fd, err := os.Open(file + ".info.json")
+	if err != nil {
+		return VideoData{}, err
+	}
+	defer fd.Close()
+
+	if err := resolver.DecodeJSONReader(fd, &video); err != nil {
+		return VideoData{}, err
+	}
+
+	return video, nil
+}
+
+// RemoveDownloadedMedia removes a downloaded file along with its metadata,
+// and removes it from the download index.
+func RemoveDownloadedMedia(file string) error {
+	if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	index.mutex.Lock()
+	for id, entries := range index.store {
+		for i, entry := range entries {
+			if entry.File == file {
+				index.store[id] = append(entries[:i], entries[i+1:]...)
+				break
+			}
+		}
+	}
+	index.mutex.Unlock()
+
+	var sidecarErr error
+	for _, sidecar := range []string{
+		file + DownloadMetadataSuffix,
+		file + ".description",
+		file + ".info.json",
+		file + ".jpg",
+	} {
+		if err := os.Remove(sidecar); err != nil && !errors.Is(err, fs.ErrNotExist) && sidecarErr == nil {
+			sidecarErr = err
+		}
+	}
+
+	return sidecarErr
+}
+
+// LocalMedia returns the local files for the video, if it has been downloaded.
+// If audio is set, an audio-only file is preferred, otherwise a file with both
+// audio and video, or a separate pair of video and audio files, is returned.
+func LocalMedia(id string, audio bool) ([2]string, bool) {
+	var files [3]string
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+
+	if !index.load() {
+		return [2]string{}, false
+	}
+
+	for _, entry := range index.store[id] {
+		if _, err := os.Stat(entry.File); err != nil {
+			continue
+		}
+
+		switch entry.MediaType {
+		case DownloadMediaAudio:
+			files[0] = entry.File
+
+		case DownloadMediaVideo:
+			files[1] = entry.File
+
+		case DownloadMediaAudioVideo:
+			files[2] = entry.File
+		}
+	}
+
+	switch {
+	case audio && files[0] != "":
+		return [2]string{files[0]}, true
+
+	case files[2] != "":
+		return [2]string{files[2]}, true
+
+	case !audio && files[1] != "" && files[0] != "":
+		return [2]string{files[1], files[0]}, true
+	}
+
+	return [2]string{}, false
+}
+
+// commandError returns the first line of the command's output,
+// or the error if there is no output.
+func commandError(output []byte, err error) string {
+	reason := strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0])
+	if reason == "" {
+		reason = err.Error()
+	}
+
+	return reason
+}
+
+// load loads the download index from the metadata files within
+// the download directory, if the directory has changed.
+func (d *downloadIndex) load() bool {
+	dir := cmd.GetOptionValue("download-dir")
+	if dir == "" {
+		return false
+	}
+	if d.store != nil && d.dir == dir {
+		return true
+	}
+
+	d.dir = dir
+	d.store = make(map[string][]DownloadMetadata)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return true
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), DownloadMetadataSuffix) {
+			continue
+		}
+
+		fd, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		var metadata DownloadMetadata
+		if err := resolver.DecodeJSONReader(fd, &metadata); err == nil && metadata.VideoID != "" {
+			metadata.File = filepath.Join(dir, strings.TrimSuffix(entry.Name(), DownloadMetadataSuffix))
+			d.add(metadata)
+		}
+
+		fd.Close()
+	}
+
+	return true
+}
+
+// add adds the metadata to the download index, replacing any
+// existing entry for the same file.
+func (d *downloadIndex) add(metadata DownloadMetadata) {
+	entries := d.store[metadata.VideoID]
+	for i, entry := range entries {
+		if entry.File == metadata.File {
+			entries[i] = metadata
+			return
+		}
+	}
+
+	d.store[metadata.VideoID] = append(entries, metadata)
+}
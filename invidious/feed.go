@@ -1,6 +1,7 @@
 package invidious
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/darkhz/invidtui/client"
@@ -26,12 +27,16 @@ type FeedVideos struct {
 }
 
 // Feed retrieves videos from a user's feed.
-func Feed(page int) (FeedData, error) {
+func Feed(page int, ctx ...context.Context) (FeedData, error) {
 	var data FeedData
 
+	if ctx == nil {
+		ctx = append(ctx, client.Ctx())
+	}
+
 	query := "auth/feed?hl=en&page=" + strconv.Itoa(page)
 
-	res, err := client.Fetch(client.Ctx(), query, client.Token())
+	res, err := client.Fetch(ctx[0], query, client.Token())
 	if err != nil {
 		return FeedData{}, err
 	}
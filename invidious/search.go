@@ -1,6 +1,7 @@
 package invidious
 
 import (
+	"context"
 	"net/url"
 	"strconv"
 
@@ -36,12 +37,19 @@ type SuggestData struct {
 }
 
 // Search retrieves search results according to the provided query.
+// Any pending requests using the client's context are cancelled.
 func Search(stype, text string, parameters map[string]string, page int, ucid ...string) ([]SearchData, int, error) {
+	client.Cancel()
+
+	return SearchContext(client.Ctx(), stype, text, parameters, page, ucid...)
+}
+
+// SearchContext retrieves search results according to the provided query,
+// using the provided context for the requests.
+func SearchContext(ctx context.Context, stype, text string, parameters map[string]string, page int, ucid ...string) ([]SearchData, int, error) {
 	var newpg int
 	var data []SearchData
 
-	client.Cancel()
-
 	for newpg = page + 1; newpg <= page+2; newpg++ {
 		query := "?q=" + url.QueryEscape(text) +
 			"&page=" + strconv.Itoa(newpg)
@@ -60,7 +68,7 @@ func Search(stype, text string, parameters map[string]string, page int, ucid ...
 			query += "&" + param + "=" + val
 		}
 
-		res, err := client.Fetch(client.Ctx(), query)
+		res, err := client.Fetch(ctx, query)
 		if err != nil {
 			return nil, newpg, err
 		}
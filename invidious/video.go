@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -80,6 +81,78 @@ func VideoThumbnail(ctx context.Context, id, image string) (*http.Response, erro
 	return res, nil
 }
 
+// VideoThumbnailURL returns the URL of the largest thumbnail of the video.
+func VideoThumbnailURL(video VideoData) string {
+	var uri string
+	var width int
+
+	for _, thumbnail := range video.Thumbnails {
+		if thumbnail.Width > width && strings.HasSuffix(thumbnail.URL, ".jpg") {
+			uri, width = thumbnail.URL, thumbnail.Width
+		}
+	}
+	if uri == "" {
+		uri = fmt.Sprintf("/vi/%s/hqdefault.jpg", video.VideoID)
+	}
+	if strings.HasPrefix(uri, "/") {
+		uri = client.Instance() + uri
+	}
+
+	return uri
+}
+
+// MuxedVideoURI returns the URI of the video's stream which contains both audio
+// and video, for players which cannot merge separate streams. The stream matching
+// the configured video resolution is preferred, otherwise the highest resolution
+// stream below it, or the lowest resolution stream is selected.
+func MuxedVideoURI(video VideoData) string {
+	var itag string
+	var resolution int
+
+	target, _ := strconv.Atoi(strings.TrimSuffix(cmd.GetOptionValue("video-res"), "p"))
+
+	for _, format := range video.FormatStreams {
+		res, _ := strconv.Atoi(strings.TrimSuffix(format.Resolution, "p"))
+
+		switch {
+		case itag == "",
+			res <= target && (res > resolution || resolution > target),
+			res > target && resolution > target && res < resolution:
+			itag, resolution = format.Itag, res
+		}
+	}
+	if itag == "" {
+		return ""
+	}
+
+	return getLatestURL(video.VideoID, itag)
+}
+
+// MediaMimeType returns the MIME type of the video's format which the provided
+// media URI refers to. If the format cannot be determined, it returns an empty string.
+func MediaMimeType(video VideoData, uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+
+	itag := u.Query().Get("itag")
+	if itag == "" {
+		return ""
+	}
+
+	for _, formats := range [][]VideoFormat{video.FormatStreams, video.AdaptiveFormats} {
+		for _, format := range formats {
+			if format.Itag == itag {
+				mime, _, _ := strings.Cut(format.Type, ";")
+				return strings.TrimSpace(mime)
+			}
+		}
+	}
+
+	return ""
+}
+
 // RenewVideoURI renews the video's media URIs.
 func RenewVideoURI(ctx context.Context, uri [2]string, video VideoData, audio bool) (VideoData, [2]string, error) {
 	if uri[0] != "" && video.LiveNow {
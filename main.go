@@ -1,18 +1,33 @@
 package main
 
 import (
+	"github.com/darkhz/invidtui/cli"
 	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/hooks"
+	"github.com/darkhz/invidtui/notify"
+	"github.com/darkhz/invidtui/scrobbler"
 	"github.com/darkhz/invidtui/ui"
 	"github.com/darkhz/invidtui/ui/keybinding"
 	"github.com/darkhz/invidtui/ui/theme"
+	"github.com/darkhz/invidtui/ui/view"
 )
 
 func main() {
 	cmd.RegisterConfigHandler(theme.GetConfigHandler(), cmd.ConfigTheme)
 	cmd.RegisterConfigHandler(keybinding.GetConfigHandler(), cmd.ConfigKeybindings)
+	cmd.RegisterConfigHandler(view.GetAutoDownloadHandler(), cmd.ConfigAutoDownload)
+	cmd.RegisterConfigHandler(hooks.GetConfigHandler(), cmd.ConfigHooks)
+	cmd.RegisterConfigHandler(scrobbler.GetConfigHandler(), cmd.ConfigScrobble)
+	cmd.RegisterConfigHandler(notify.GetConfigHandler(), cmd.ConfigNotify)
 
+	cmd.SetInstanceFunc(cli.NeedsInstance)
 	cmd.Init()
 
+	if commands := cmd.Commands(); commands != nil {
+		cli.Run(commands)
+		return
+	}
+
 	ui.SetupUI()
 
 	cmd.SaveSettings()
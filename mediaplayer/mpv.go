@@ -75,8 +75,10 @@ func (m *MPV) LoadFile(title string, duration int64, audio bool, files [2]string
 	if duration > 0 {
 		options = append(options, "length="+strconv.FormatInt(duration, 10))
 	}
-	if len(files) == 2 {
-		options = append(options, "audio-file="+files[1])
+	if files[1] != "" {
+		// Quote the path with its length, so that any commas within
+		// the path are not treated as option separators.
+		options = append(options, "audio-file=%"+strconv.Itoa(len(files[1]))+"%"+files[1])
 	}
 
 	_, err := m.Call("loadfile", files[0], "replace", "-1", strings.Join(options, ","))
@@ -395,6 +397,9 @@ func (m *MPV) eventListener() {
 	m.Call("observe_property", 1, "eof-reached")
 	m.Call("observe_property", 2, "paused-for-cache")
 	m.Call("observe_property", 3, "seeking")
+	m.Call("observe_property", 4, "pause")
+
+	var loaded bool
 
 	for event := range events {
 		mediaEvent := EventNone
@@ -415,16 +420,25 @@ func (m *MPV) eventListener() {
 					mediaEvent = EventInProgress
 				}
 			}
+
+		case 4:
+			if paused, ok := event.Data.(bool); ok && paused && loaded {
+				mediaEvent = EventPause
+			}
 		}
 
 		switch event.Name {
 		case "start-file":
+			loaded = false
+
 			m.Set("pause", "yes")
 			m.Set("pause", "no")
 
 			mediaEvent = EventStart
 
 		case "end-file":
+			loaded = false
+
 			if event.Reason == "eof" {
 				mediaEvent = EventEnd
 			}
@@ -440,6 +454,7 @@ func (m *MPV) eventListener() {
 			}
 
 		case "file-loaded":
+			loaded = true
 			mediaEvent = EventInProgress
 
 		}
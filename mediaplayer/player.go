@@ -47,8 +47,8 @@ type MediaPlayer interface {
 
 // MediaPlayerSettings stores the media player's settings.
 type MediaPlayerSettings struct {
-	current string
-	handler func(e MediaEvent)
+	current, local string
+	handler        func(e MediaEvent)
 
 	mutex sync.Mutex
 }
@@ -69,6 +69,7 @@ const (
 	EventLoading
 	EventInProgress
 	EventError
+	EventPause
 )
 
 type RepeatMode int
@@ -83,13 +84,14 @@ var (
 	settings MediaPlayerSettings
 
 	players = map[string]MediaPlayer{
-		"mpv": &mpv,
+		"mpv":  &mpv,
+		"upnp": &upnp,
 	}
 )
 
 // Init launches the provided player.
 func Init(player string, properties MediaPlayerProperties) error {
-	settings.current = player
+	settings.current, settings.local = player, player
 	settings.handler = func(e MediaEvent) {}
 
 	return players[player].Init(properties)
@@ -114,5 +116,54 @@ func SetEventHandler(handler func(e MediaEvent)) {
 
 // Player returns the currently selected player.
 func Player() MediaPlayer {
+	settings.mutex.Lock()
+	defer settings.mutex.Unlock()
+
 	return players[settings.current]
 }
+
+// Cast stops the current playback, and selects the UPnP renderer as the player.
+func Cast(renderer Renderer) {
+	Player().Stop()
+	upnp.Open(renderer)
+
+	settings.mutex.Lock()
+	settings.current = "upnp"
+	settings.mutex.Unlock()
+}
+
+// StopCasting disconnects from the UPnP renderer, and selects the local player.
+func StopCasting() {
+	settings.mutex.Lock()
+	casting := settings.current == "upnp"
+	settings.current = settings.local
+	settings.mutex.Unlock()
+
+	if casting {
+		upnp.Exit()
+	}
+}
+
+// CastRenderer returns the renderer which is currently being cast to, if any.
+func CastRenderer() (Renderer, bool) {
+	if !Casting() {
+		return Renderer{}, false
+	}
+
+	upnp.mutex.Lock()
+	defer upnp.mutex.Unlock()
+
+	if upnp.renderer == nil {
+		return Renderer{}, false
+	}
+
+	return *upnp.renderer, true
+}
+
+// Casting returns whether playback is being cast to a UPnP renderer.
+func Casting() bool {
+	settings.mutex.Lock()
+	defer settings.mutex.Unlock()
+
+	return settings.current == "upnp"
+}
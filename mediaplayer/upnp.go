@@ -0,0 +1,797 @@
+package mediaplayer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UPnP describes a UPnP media renderer, which is controlled
+// via its AVTransport and RenderingControl services.
+type UPnP struct {
+	renderer *Renderer
+
+	state              string
+	position, duration int64
+	volume             int
+	muted              bool
+	loop               RepeatMode
+	mimeType           string
+
+	stopped, finished bool
+	generation        int
+
+	closed chan struct{}
+	cancel context.CancelFunc
+
+	client *http.Client
+
+	mutex sync.Mutex
+}
+
+// Renderer describes a UPnP media renderer on the network.
+type Renderer struct {
+	Name     string
+	Location string
+
+	transport, rendering string
+}
+
+// upnpDescription describes a UPnP device description.
+type upnpDescription struct {
+	URLBase string     `xml:"URLBase"`
+	Device  upnpDevice `xml:"device"`
+}
+
+// upnpDevice describes a UPnP device and its services.
+type upnpDevice struct {
+	FriendlyName string `xml:"friendlyName"`
+	Services     []struct {
+		ServiceType string `xml:"serviceType"`
+		ControlURL  string `xml:"controlURL"`
+	} `xml:"serviceList>service"`
+	Devices []upnpDevice `xml:"deviceList>device"`
+}
+
+// The UPnP services, and the SSDP multicast address.
+const (
+	upnpTransport = "urn:schemas-upnp-org:service:AVTransport:1"
+	upnpRendering = "urn:schemas-upnp-org:service:RenderingControl:1"
+
+	ssdpAddress = "239.255.255.250:1900"
+)
+
+var upnp UPnP
+
+// DiscoverRenderers searches the network for UPnP media renderers
+// which respond within the provided timeout.
+func DiscoverRenderers(ctx context.Context, timeout time.Duration) ([]Renderer, error) {
+	var renderers []Renderer
+
+	addr, err := net.ResolveUDPAddr("udp4", ssdpAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, fmt.Errorf("UPnP: Cannot start discovery: %w", err)
+	}
+	defer conn.Close()
+
+	search := strings.Join([]string{
+		"M-SEARCH * HTTP/1.1",
+		"HOST: " + ssdpAddress,
+		`MAN: "ssdp:discover"`,
+		"MX: 2",
+		"ST: " + upnpTransport,
+		"", "",
+	}, "\r\n")
+
+	if _, err := conn.WriteToUDP([]byte(search), addr); err != nil {
+		return nil, fmt.Errorf("UPnP: Cannot send discovery request: %w", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	go func() {
+		<-ctx.Done()
+		conn.SetReadDeadline(time.Now())
+	}()
+
+	locations := make(map[string]struct{})
+	buf := make([]byte, 2048)
+
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			break
+		}
+
+		res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+
+		location := res.Header.Get("Location")
+		if _, ok := locations[location]; ok || location == "" {
+			continue
+		}
+
+		locations[location] = struct{}{}
+
+		renderer, err := NewRenderer(ctx, location)
+		if err != nil {
+			continue
+		}
+
+		renderers = append(renderers, renderer)
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return renderers, nil
+}
+
+// NewRenderer returns the renderer described at the provided location.
+func NewRenderer(ctx context.Context, location string) (Renderer, error) {
+	var description upnpDescription
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return Renderer{}, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Renderer{}, err
+	}
+	defer res.Body.Close()
+
+	if err := xml.NewDecoder(res.Body).Decode(&description); err != nil {
+		return Renderer{}, fmt.Errorf("UPnP: Invalid device description: %w", err)
+	}
+
+	base, err := url.Parse(location)
+	if err != nil {
+		return Renderer{}, err
+	}
+	if description.URLBase != "" {
+		if u, err := url.Parse(description.URLBase); err == nil {
+			base = u
+		}
+	}
+
+	renderer := Renderer{Location: location}
+
+	devices := []upnpDevice{description.Device}
+	for len(devices) > 0 {
+		device := devices[0]
+		devices = append(devices[1:], device.Devices...)
+
+		for _, service := range device.Services {
+			control, err := base.Parse(strings.TrimSpace(service.ControlURL))
+			if err != nil {
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(service.ServiceType, "urn:schemas-upnp-org:service:AVTransport:"):
+				if renderer.transport == "" {
+					renderer.Name = device.FriendlyName
+					renderer.transport = control.String()
+				}
+
+			case strings.HasPrefix(service.ServiceType, "urn:schemas-upnp-org:service:RenderingControl:"):
+				if renderer.rendering == "" {
+					renderer.rendering = control.String()
+				}
+			}
+		}
+	}
+
+	if renderer.transport == "" {
+		return Renderer{}, fmt.Errorf("UPnP: %s is not a media renderer", location)
+	}
+	if renderer.Name == "" {
+		renderer.Name = base.Host
+	}
+
+	return renderer, nil
+}
+
+// Open connects to the renderer, and starts monitoring its playback state.
+func (u *UPnP) Open(renderer Renderer) {
+	u.Exit()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	u.mutex.Lock()
+	u.renderer = &renderer
+	u.state, u.position, u.duration = "", 0, 0
+	u.volume, u.muted = -1, false
+	u.stopped, u.finished = true, false
+	u.closed = make(chan struct{})
+	u.cancel = cancel
+	u.client = &http.Client{Timeout: 5 * time.Second}
+	u.mutex.Unlock()
+
+	go u.watch(ctx)
+}
+
+// Init initializes the renderer. Renderers are connected to via Open instead.
+func (u *UPnP) Init(properties MediaPlayerProperties) error {
+	return nil
+}
+
+// Exit stops the playback, and disconnects from the renderer.
+func (u *UPnP) Exit() {
+	u.mutex.Lock()
+	renderer, cancel, closed := u.renderer, u.cancel, u.closed
+	u.renderer = nil
+	u.mutex.Unlock()
+
+	if renderer == nil {
+		return
+	}
+
+	cancel()
+	u.action(renderer.transport, upnpTransport, "Stop", nil)
+	close(closed)
+}
+
+// Exited returns whether the renderer is disconnected.
+func (u *UPnP) Exited() bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	return u.renderer == nil
+}
+
+// SendQuit does nothing, since renderers are not launched by the application.
+func (u *UPnP) SendQuit(socket string) {
+}
+
+// LoadFile loads the provided file into the renderer. Renderers cannot merge
+// separate audio and video streams, so only the first file is loaded.
+func (u *UPnP) LoadFile(title string, duration int64, audio bool, files [2]string) error {
+	if files[0] == "" {
+		return fmt.Errorf("UPnP: Unable to load empty fileset")
+	}
+	if !strings.HasPrefix(files[0], "http") {
+		return fmt.Errorf("UPnP: Cannot cast local file for %s", title)
+	}
+
+	u.mutex.Lock()
+	u.generation++
+	u.stopped, u.finished = true, false
+	u.state, u.position, u.duration = "STOPPED", 0, duration
+	mimeType := u.mimeType
+	u.mutex.Unlock()
+
+	_, err := u.transportAction("SetAVTransportURI", [][2]string{
+		{"CurrentURI", files[0]},
+		{"CurrentURIMetaData", upnpMetadata(title, files[0], mimeType, audio)},
+	})
+	if err != nil {
+		return fmt.Errorf("UPnP: Unable to load %s: %w", title, err)
+	}
+
+	EventHandler(EventStart)
+
+	return nil
+}
+
+// Play starts the playback.
+func (u *UPnP) Play() {
+	u.mutex.Lock()
+	u.stopped = false
+	u.mutex.Unlock()
+
+	u.transportAction("Play", [][2]string{{"Speed", "1"}})
+}
+
+// Stop stops the playback.
+func (u *UPnP) Stop() {
+	u.mutex.Lock()
+	u.generation++
+	u.stopped = true
+	u.state = "STOPPED"
+	u.mutex.Unlock()
+
+	u.transportAction("Stop", nil)
+}
+
+// SeekForward seeks the track forward by 1s.
+func (u *UPnP) SeekForward() {
+	u.SetPosition(u.Position() + 1)
+}
+
+// SeekBackward seeks the track backward by 1s.
+func (u *UPnP) SeekBackward() {
+	u.SetPosition(u.Position() - 1)
+}
+
+// SeekToPosition seeks the track relative to the current position.
+func (u *UPnP) SeekToPosition(seekpos string) {
+	offset, err := strconv.ParseFloat(strings.TrimSpace(seekpos), 64)
+	if err != nil {
+		return
+	}
+
+	u.SetPosition(u.Position() + int64(offset))
+}
+
+// SetPosition sets the absolute position for the track.
+func (u *UPnP) SetPosition(position int64) {
+	if position < 0 {
+		position = 0
+	}
+
+	_, err := u.transportAction("Seek", [][2]string{
+		{"Unit", "REL_TIME"},
+		{"Target", fmt.Sprintf("%02d:%02d:%02d", position/3600, (position/60)%60, position%60)},
+	})
+	if err != nil {
+		return
+	}
+
+	u.mutex.Lock()
+	u.position = position
+	u.mutex.Unlock()
+}
+
+// Position returns the seek position.
+func (u *UPnP) Position() int64 {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	return u.position
+}
+
+// Duration returns the total duration of the track.
+func (u *UPnP) Duration() int64 {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	return u.duration
+}
+
+// Paused returns whether playback is paused or not.
+func (u *UPnP) Paused() bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	return u.state == "PAUSED_PLAYBACK"
+}
+
+// TogglePaused toggles pausing the playback.
+func (u *UPnP) TogglePaused() {
+	u.mutex.Lock()
+	state, finished := u.state, u.finished
+	u.mutex.Unlock()
+
+	switch {
+	case finished:
+		u.SetPosition(0)
+		u.Play()
+
+	case state == "PLAYING" || state == "TRANSITIONING":
+		u.transportAction("Pause", nil)
+
+	default:
+		u.Play()
+	}
+}
+
+// Muted returns whether playback is muted.
+func (u *UPnP) Muted() bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	return u.muted
+}
+
+// ToggleMuted toggles muting of the playback.
+func (u *UPnP) ToggleMuted() {
+	mute := "1"
+	if u.Muted() {
+		mute = "0"
+	}
+
+	if _, err := u.renderingAction("SetMute", [][2]string{
+		{"Channel", "Master"},
+		{"DesiredMute", mute},
+	}); err == nil {
+		u.mutex.Lock()
+		u.muted = mute == "1"
+		u.mutex.Unlock()
+	}
+}
+
+// SetLoopMode sets the loop mode. Since renderers play a single
+// file at a time, only the file repeat mode is handled here.
+func (u *UPnP) SetLoopMode(mode RepeatMode) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	u.loop = mode
+}
+
+// Idle returns if the player is idle.
+func (u *UPnP) Idle() bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	return u.state == "" || u.state == "STOPPED" || u.state == "NO_MEDIA_PRESENT"
+}
+
+// Finished returns if the playback has finished.
+func (u *UPnP) Finished() bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	return u.finished
+}
+
+// Buffering returns if the renderer is buffering.
+func (u *UPnP) Buffering() bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	return u.state == "TRANSITIONING"
+}
+
+// BufferPercentage returns -1, since renderers do not report their buffer state.
+func (u *UPnP) BufferPercentage() int {
+	return -1
+}
+
+// Volume returns the volume.
+func (u *UPnP) Volume() int {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	return u.volume
+}
+
+// VolumeIncrease increments the volume by 1.
+func (u *UPnP) VolumeIncrease() {
+	if vol := u.Volume(); vol >= 0 {
+		u.setVolume(vol + 1)
+	}
+}
+
+// VolumeDecrease decreases the volume by 1.
+func (u *UPnP) VolumeDecrease() {
+	if vol := u.Volume(); vol >= 0 {
+		u.setVolume(vol - 1)
+	}
+}
+
+// WaitClosed waits for the renderer to be disconnected.
+func (u *UPnP) WaitClosed() {
+	u.mutex.Lock()
+	closed := u.closed
+	u.mutex.Unlock()
+
+	if closed != nil {
+		<-closed
+	}
+}
+
+// Call is not supported by renderers.
+func (u *UPnP) Call(args ...interface{}) (interface{}, error) {
+	return nil, fmt.Errorf("UPnP: Commands are not supported")
+}
+
+// Get gets a property from the renderer. Only the volume is supported.
+func (u *UPnP) Get(prop string) (interface{}, error) {
+	if prop != "volume" {
+		return nil, fmt.Errorf("UPnP: Property %q is not supported", prop)
+	}
+
+	return u.Volume(), nil
+}
+
+// Set sets a property in the renderer. Only the volume, and the
+// MIME type of the media to be loaded next are supported.
+func (u *UPnP) Set(prop string, value interface{}) error {
+	if prop == "mime-type" {
+		u.mutex.Lock()
+		u.mimeType = fmt.Sprint(value)
+		u.mutex.Unlock()
+
+		return nil
+	}
+	if prop != "volume" {
+		return fmt.Errorf("UPnP: Property %q is not supported", prop)
+	}
+
+	volume, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+	if err != nil {
+		return fmt.Errorf("UPnP: Invalid volume %v", value)
+	}
+
+	return u.setVolume(int(volume))
+}
+
+// setVolume sets the renderer's volume.
+func (u *UPnP) setVolume(volume int) error {
+	if volume < 0 {
+		volume = 0
+	} else if volume > 100 {
+		volume = 100
+	}
+
+	_, err := u.renderingAction("SetVolume", [][2]string{
+		{"Channel", "Master"},
+		{"DesiredVolume", strconv.Itoa(volume)},
+	})
+	if err != nil {
+		return err
+	}
+
+	u.mutex.Lock()
+	u.volume = volume
+	u.mutex.Unlock()
+
+	return nil
+}
+
+// watch polls the renderer's playback state every second, and sends
+// the corresponding media events when the state changes.
+//
+//gocyclo:ignore
+func (u *UPnP) watch(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+		}
+
+		u.mutex.Lock()
+		generation := u.generation
+		u.mutex.Unlock()
+
+		transport, err := u.transportAction("GetTransportInfo", nil)
+		if err != nil {
+			continue
+		}
+
+		info, err := u.transportAction("GetPositionInfo", nil)
+		if err != nil {
+			continue
+		}
+
+		volume, mute := -1, false
+		if result, err := u.renderingAction("GetVolume", [][2]string{{"Channel", "Master"}}); err == nil {
+			if v, err := strconv.Atoi(result["CurrentVolume"]); err == nil {
+				volume = v
+			}
+		}
+		if result, err := u.renderingAction("GetMute", [][2]string{{"Channel", "Master"}}); err == nil {
+			mute = result["CurrentMute"] == "1" || result["CurrentMute"] == "true"
+		}
+
+		event := EventNone
+		restart := false
+
+		u.mutex.Lock()
+		if generation != u.generation {
+			u.mutex.Unlock()
+			continue
+		}
+
+		previous, state := u.state, transport["CurrentTransportState"]
+
+		u.state = state
+		u.volume, u.muted = volume, mute
+		u.position = upnpSeconds(info["RelTime"])
+		if duration := upnpSeconds(info["TrackDuration"]); duration > 0 {
+			u.duration = duration
+		}
+
+		switch {
+		case state == previous:
+
+		case state == "TRANSITIONING":
+			event = EventLoading
+
+		case state == "PLAYING":
+			event = EventInProgress
+
+		case state == "PAUSED_PLAYBACK" && previous == "PLAYING":
+			event = EventPause
+
+		case (state == "STOPPED" || state == "NO_MEDIA_PRESENT") && !u.stopped &&
+			(previous == "PLAYING" || previous == "PAUSED_PLAYBACK" || previous == "TRANSITIONING"):
+			if u.loop == RepeatModeFile {
+				restart = true
+				break
+			}
+
+			u.finished = true
+			event = EventEnd
+		}
+		u.mutex.Unlock()
+
+		if restart {
+			u.Play()
+		}
+		if event != EventNone {
+			EventHandler(event)
+		}
+	}
+}
+
+// transportAction invokes an action on the renderer's AVTransport service.
+func (u *UPnP) transportAction(action string, args [][2]string) (map[string]string, error) {
+	u.mutex.Lock()
+	renderer := u.renderer
+	u.mutex.Unlock()
+
+	if renderer == nil {
+		return nil, fmt.Errorf("UPnP: Not connected to a renderer")
+	}
+
+	return u.action(renderer.transport, upnpTransport, action, args)
+}
+
+// renderingAction invokes an action on the renderer's RenderingControl service.
+func (u *UPnP) renderingAction(action string, args [][2]string) (map[string]string, error) {
+	u.mutex.Lock()
+	renderer := u.renderer
+	u.mutex.Unlock()
+
+	if renderer == nil {
+		return nil, fmt.Errorf("UPnP: Not connected to a renderer")
+	}
+	if renderer.rendering == "" {
+		return nil, fmt.Errorf("UPnP: Renderer does not support volume control")
+	}
+
+	return u.action(renderer.rendering, upnpRendering, action, args)
+}
+
+// action invokes a SOAP action on the service at the control URL,
+// and returns the arguments of the response.
+func (u *UPnP) action(control, service, action string, args [][2]string) (map[string]string, error) {
+	var body bytes.Buffer
+
+	body.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
+	body.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" `)
+	body.WriteString(`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(&body, `<u:%s xmlns:u="%s"><InstanceID>0</InstanceID>`, action, service)
+	for _, arg := range args {
+		fmt.Fprintf(&body, "<%s>", arg[0])
+		xml.EscapeText(&body, []byte(arg[1]))
+		fmt.Fprintf(&body, "</%s>", arg[0])
+	}
+	fmt.Fprintf(&body, `</u:%s></s:Body></s:Envelope>`, action)
+
+	req, err := http.NewRequest(http.MethodPost, control, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, service, action))
+
+	u.mutex.Lock()
+	client := u.client
+	u.mutex.Unlock()
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	result, err := upnpResult(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		message := result["errorDescription"]
+		if message == "" {
+			message = res.Status
+		}
+
+		return nil, fmt.Errorf("%s failed: %s", action, message)
+	}
+
+	return result, nil
+}
+
+// upnpResult returns the values of the elements within a SOAP response.
+func upnpResult(body io.Reader) (map[string]string, error) {
+	var name string
+
+	result := make(map[string]string)
+	decoder := xml.NewDecoder(body)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			name = t.Name.Local
+
+		case xml.CharData:
+			if name != "" {
+				result[name] += string(t)
+			}
+
+		case xml.EndElement:
+			name = ""
+		}
+	}
+}
+
+// upnpMetadata returns the DIDL-Lite metadata for the media.
+// If the MIME type is empty, a generic MP4 type is used.
+func upnpMetadata(title, uri, mime string, audio bool) string {
+	var metadata bytes.Buffer
+
+	class, generic := "object.item.videoItem", "video/mp4"
+	if audio {
+		class, generic = "object.item.audioItem.musicTrack", "audio/mp4"
+	}
+	if mime == "" {
+		mime = generic
+	}
+
+	metadata.WriteString(`<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/" `)
+	metadata.WriteString(`xmlns:dc="http://purl.org/dc/elements/1.1/" `)
+	metadata.WriteString(`xmlns:upnp="urn:schemas-upnp-org:metadata-1-0/upnp/">`)
+	metadata.WriteString(`<item id="0" parentID="-1" restricted="1"><dc:title>`)
+	xml.EscapeText(&metadata, []byte(title))
+	fmt.Fprintf(&metadata, `</dc:title><upnp:class>%s</upnp:class>`, class)
+	fmt.Fprintf(&metadata, `<res protocolInfo="http-get:*:%s:*">`, mime)
+	xml.EscapeText(&metadata, []byte(uri))
+	metadata.WriteString(`</res></item></DIDL-Lite>`)
+
+	return metadata.String()
+}
+
+// upnpSeconds converts a duration of the form "H:MM:SS(.F)" to seconds.
+func upnpSeconds(duration string) int64 {
+	var seconds int64
+
+	duration, _, _ = strings.Cut(strings.TrimSpace(duration), ".")
+
+	for _, part := range strings.Split(duration, ":") {
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0
+		}
+
+		seconds = seconds*60 + value
+	}
+
+	return seconds
+}
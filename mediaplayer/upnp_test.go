@@ -0,0 +1,372 @@
+package mediaplayer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testRenderer describes a fake UPnP media renderer.
+type testRenderer struct {
+	server *httptest.Server
+
+	state, relTime string
+	volume         int
+	fail           string
+
+	actions []testAction
+
+	mutex sync.Mutex
+}
+
+// testAction describes an action invoked on the fake renderer.
+type testAction struct {
+	service, name string
+	args          map[string]string
+}
+
+const testDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <friendlyName>Root Device</friendlyName>
+    <deviceList>
+      <device>
+        <friendlyName>Living Room TV</friendlyName>
+        <serviceList>
+          <service>
+            <serviceType>urn:schemas-upnp-org:service:RenderingControl:1</serviceType>
+            <controlURL>/control/rendering</controlURL>
+          </service>
+          <service>
+            <serviceType>urn:schemas-upnp-org:service:AVTransport:1</serviceType>
+            <controlURL>control/transport</controlURL>
+          </service>
+        </serviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+
+// newTestRenderer starts a fake renderer, and returns its description.
+func newTestRenderer(t *testing.T) (*testRenderer, Renderer) {
+	r := &testRenderer{state: "NO_MEDIA_PRESENT", relTime: "0:00:00", volume: 50}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/description.xml", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, testDescription)
+	})
+	mux.HandleFunc("/control/transport", r.handle)
+	mux.HandleFunc("/control/rendering", r.handle)
+
+	r.server = httptest.NewServer(mux)
+	t.Cleanup(r.server.Close)
+
+	renderer, err := NewRenderer(context.Background(), r.server.URL+"/description.xml")
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+
+	return r, renderer
+}
+
+// handle responds to a SOAP action.
+func (r *testRenderer) handle(w http.ResponseWriter, req *http.Request) {
+	service, name, _ := strings.Cut(strings.Trim(req.Header.Get("SOAPAction"), `"`), "#")
+
+	args, err := upnpResult(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.actions = append(r.actions, testAction{service, name, args})
+
+	if name == r.fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault>`)
+		fmt.Fprint(w, `<detail><UPnPError><errorCode>714</errorCode>`)
+		fmt.Fprint(w, `<errorDescription>Illegal MIME-type</errorDescription></UPnPError></detail>`)
+		fmt.Fprint(w, `</s:Fault></s:Body></s:Envelope>`)
+
+		return
+	}
+
+	result := map[string]string{}
+	switch name {
+	case "GetTransportInfo":
+		result["CurrentTransportState"] = r.state
+
+	case "GetPositionInfo":
+		result["RelTime"] = r.relTime
+		result["TrackDuration"] = "0:03:20"
+
+	case "GetVolume":
+		result["CurrentVolume"] = fmt.Sprint(r.volume)
+
+	case "GetMute":
+		result["CurrentMute"] = "0"
+
+	case "Play":
+		r.state = "PLAYING"
+
+	case "Pause":
+		r.state = "PAUSED_PLAYBACK"
+
+	case "Stop":
+		r.state = "STOPPED"
+
+	case "Seek":
+		r.relTime = args["Target"]
+	}
+
+	fmt.Fprintf(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`)
+	fmt.Fprintf(w, `<u:%sResponse xmlns:u="%s">`, name, service)
+	for key, value := range result {
+		fmt.Fprintf(w, "<%s>%s</%s>", key, value, key)
+	}
+	fmt.Fprintf(w, `</u:%sResponse></s:Body></s:Envelope>`, name)
+}
+
+// setState sets the transport state reported by the renderer.
+func (r *testRenderer) setState(state string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.state = state
+}
+
+// lastAction returns the last invoked action with the provided name.
+func (r *testRenderer) lastAction(name string) (testAction, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := len(r.actions) - 1; i >= 0; i-- {
+		if r.actions[i].name == name {
+			return r.actions[i], true
+		}
+	}
+
+	return testAction{}, false
+}
+
+// openTestPlayer connects a player to the fake renderer, and
+// returns the channel to which the media events are sent.
+func openTestPlayer(t *testing.T, renderer Renderer) (*UPnP, chan MediaEvent) {
+	var u UPnP
+
+	events := make(chan MediaEvent, 16)
+	SetEventHandler(func(e MediaEvent) {
+		select {
+		case events <- e:
+		default:
+		}
+	})
+
+	u.Open(renderer)
+	t.Cleanup(u.Exit)
+
+	return &u, events
+}
+
+// waitEvent waits for the provided media event to be sent.
+func waitEvent(t *testing.T, events chan MediaEvent, event MediaEvent) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e := <-events:
+			if e == event {
+				return
+			}
+
+		case <-timeout:
+			t.Fatalf("Timed out waiting for event %d", event)
+		}
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	r, renderer := newTestRenderer(t)
+
+	if renderer.Name != "Living Room TV" {
+		t.Errorf("Name = %q, want %q", renderer.Name, "Living Room TV")
+	}
+	if want := r.server.URL + "/control/transport"; renderer.transport != want {
+		t.Errorf("transport = %q, want %q", renderer.transport, want)
+	}
+	if want := r.server.URL + "/control/rendering"; renderer.rendering != want {
+		t.Errorf("rendering = %q, want %q", renderer.rendering, want)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `<root><device><friendlyName>Speaker</friendlyName></device></root>`)
+	}))
+	defer server.Close()
+
+	if _, err := NewRenderer(context.Background(), server.URL); err == nil {
+		t.Error("NewRenderer: expected an error for a device without AVTransport")
+	}
+}
+
+func TestUPnPControl(t *testing.T) {
+	r, renderer := newTestRenderer(t)
+	u, events := openTestPlayer(t, renderer)
+
+	if err := u.LoadFile("local", 10, true, [2]string{"/home/user/track.m4a"}); err == nil {
+		t.Error("LoadFile: expected an error for a local file")
+	}
+
+	if err := u.Set("mime-type", "audio/webm"); err != nil {
+		t.Fatalf("Set(mime-type): %v", err)
+	}
+
+	uri := "http://example.com/latest_version?id=abc&itag=251"
+	if err := u.LoadFile("Rock & Roll", 200, true, [2]string{uri}); err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	waitEvent(t, events, EventStart)
+
+	load, _ := r.lastAction("SetAVTransportURI")
+	if load.args["CurrentURI"] != uri {
+		t.Errorf("CurrentURI = %q, want %q", load.args["CurrentURI"], uri)
+	}
+	for _, want := range []string{
+		`http-get:*:audio/webm:*`,
+		`object.item.audioItem.musicTrack`,
+		`Rock &amp; Roll`,
+		`id=abc&amp;itag=251`,
+	} {
+		if !strings.Contains(load.args["CurrentURIMetaData"], want) {
+			t.Errorf("CurrentURIMetaData does not contain %q: %s", want, load.args["CurrentURIMetaData"])
+		}
+	}
+
+	u.Play()
+	if play, ok := r.lastAction("Play"); !ok || play.args["Speed"] != "1" {
+		t.Errorf("Play: invoked %v, args %v", ok, play.args)
+	}
+
+	u.SetPosition(3725)
+	if seek, _ := r.lastAction("Seek"); seek.args["Target"] != "01:02:05" || seek.args["Unit"] != "REL_TIME" {
+		t.Errorf("Seek: args %v", seek.args)
+	}
+	if position := u.Position(); position != 3725 {
+		t.Errorf("Position() = %d, want 3725", position)
+	}
+
+	if err := u.Set("volume", 150); err != nil {
+		t.Fatalf("Set(volume): %v", err)
+	}
+	if volume, _ := r.lastAction("SetVolume"); volume.args["DesiredVolume"] != "100" {
+		t.Errorf("SetVolume: args %v", volume.args)
+	}
+	if err := u.Set("volume", "loud"); err == nil {
+		t.Error("Set(volume): expected an error for an invalid volume")
+	}
+	if err := u.Set("speed", 2); err == nil {
+		t.Error("Set(speed): expected an error for an unsupported property")
+	}
+
+	u.ToggleMuted()
+	if mute, _ := r.lastAction("SetMute"); mute.args["DesiredMute"] != "1" || !u.Muted() {
+		t.Errorf("SetMute: args %v, muted %v", mute.args, u.Muted())
+	}
+
+	r.mutex.Lock()
+	r.fail = "SetAVTransportURI"
+	r.mutex.Unlock()
+
+	err := u.LoadFile("Track", 200, false, [2]string{uri})
+	if err == nil || !strings.Contains(err.Error(), "Illegal MIME-type") {
+		t.Errorf("LoadFile: error %v, want the renderer's error description", err)
+	}
+}
+
+func TestUPnPEvents(t *testing.T) {
+	r, renderer := newTestRenderer(t)
+	u, events := openTestPlayer(t, renderer)
+
+	if err := u.LoadFile("Track", 200, false, [2]string{"http://example.com/video.mp4"}); err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+
+	u.Play()
+	waitEvent(t, events, EventInProgress)
+
+	if u.Duration() != 200 || u.Volume() != 50 {
+		t.Errorf("Duration() = %d, Volume() = %d, want 200, 50", u.Duration(), u.Volume())
+	}
+
+	u.TogglePaused()
+	waitEvent(t, events, EventPause)
+
+	if !u.Paused() {
+		t.Error("Paused() = false, want true")
+	}
+
+	u.TogglePaused()
+	waitEvent(t, events, EventInProgress)
+
+	r.setState("STOPPED")
+	waitEvent(t, events, EventEnd)
+
+	if !u.Finished() {
+		t.Error("Finished() = false, want true")
+	}
+}
+
+func TestUPnPSeconds(t *testing.T) {
+	tests := []struct {
+		duration string
+		seconds  int64
+	}{
+		{"0:00:00", 0},
+		{"0:03:20", 200},
+		{"1:02:05", 3725},
+		{"01:02:05.500", 3725},
+		{" 0:00:42 ", 42},
+		{"12:34", 754},
+		{"59", 59},
+		{"", 0},
+		{"NOT_IMPLEMENTED", 0},
+		{"0:xx:10", 0},
+	}
+
+	for _, test := range tests {
+		if seconds := upnpSeconds(test.duration); seconds != test.seconds {
+			t.Errorf("upnpSeconds(%q) = %d, want %d", test.duration, seconds, test.seconds)
+		}
+	}
+}
+
+func TestUPnPMetadata(t *testing.T) {
+	tests := []struct {
+		mime  string
+		audio bool
+		want  []string
+	}{
+		{"", false, []string{"object.item.videoItem", "http-get:*:video/mp4:*"}},
+		{"", true, []string{"object.item.audioItem.musicTrack", "http-get:*:audio/mp4:*"}},
+		{"video/webm", false, []string{"object.item.videoItem", "http-get:*:video/webm:*"}},
+		{"audio/webm", true, []string{"object.item.audioItem.musicTrack", "http-get:*:audio/webm:*"}},
+	}
+
+	for _, test := range tests {
+		metadata := upnpMetadata("<Title>", "http://host/a?b=1&c=2", test.mime, test.audio)
+
+		for _, want := range append(test.want, "&lt;Title&gt;", "b=1&amp;c=2") {
+			if !strings.Contains(metadata, want) {
+				t.Errorf("upnpMetadata(%q, %v) does not contain %q: %s", test.mime, test.audio, want, metadata)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+//go:build linux
+// +build linux
+
+package notify
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// The notification service's bus name, object path and method.
+const (
+	notifyName   = "org.freedesktop.Notifications"
+	notifyPath   = "/org/freedesktop/Notifications"
+	notifyMethod = "org.freedesktop.Notifications.Notify"
+)
+
+var bus struct {
+	conn *dbus.Conn
+	err  error
+
+	once sync.Once
+}
+
+// sendDBus sends the notification via the org.freedesktop.Notifications service,
+// replacing the notification with the provided ID, and returns the new notification ID.
+func sendDBus(notification Notification, replaces uint32) (uint32, error) {
+	var id uint32
+
+	bus.once.Do(func() {
+		bus.conn, bus.err = dbus.ConnectSessionBus()
+	})
+	if bus.err != nil {
+		return 0, fmt.Errorf("Cannot connect to session bus: %w", bus.err)
+	}
+
+	hints := map[string]dbus.Variant{}
+	if notification.Icon != "" {
+		hints["image-path"] = dbus.MakeVariant("file://" + notification.Icon)
+	}
+
+	err := bus.conn.Object(notifyName, notifyPath).Call(
+		notifyMethod, 0,
+		"invidtui", replaces, notification.Icon,
+		notification.Title, notification.Body,
+		[]string{}, hints, int32(-1),
+	).Store(&id)
+
+	return id, err
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package notify
+
+import "fmt"
+
+// sendDBus is only supported on Linux, so the configured command is used instead.
+func sendDBus(notification Notification, replaces uint32) (uint32, error) {
+	return 0, fmt.Errorf("D-Bus notifications are not supported on this platform")
+}
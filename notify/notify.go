@@ -0,0 +1,230 @@
+package notify
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/client"
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/platform"
+	"github.com/knadh/koanf/v2"
+)
+
+// Notifier describes the desktop notifications configuration.
+type Notifier struct {
+	command      string
+	categories   map[Category]bool
+	feedInterval time.Duration
+
+	replaces uint32
+
+	errorHandler func(err error)
+
+	mutex sync.Mutex
+}
+
+// Notification describes a desktop notification.
+type Notification struct {
+	Category    Category
+	Title, Body string
+	Icon        string
+}
+
+// Category describes a notification category.
+type Category string
+
+// The different notification categories.
+const (
+	CategoryTrack    Category = "track-start"
+	CategoryDownload Category = "download"
+	CategoryFeed     Category = "feed"
+)
+
+// DefaultFeedInterval is the default interval at which the feed is checked for new items.
+const DefaultFeedInterval = 15 * time.Minute
+
+var notifier Notifier
+
+// GetConfigHandler returns the notifications configuration handler.
+func GetConfigHandler() *Notifier {
+	return &notifier
+}
+
+// Parse parses the notifications configuration.
+func (n *Notifier) Parse(k *koanf.Koanf, dir string) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.command = strings.TrimSpace(k.String("notifications.command"))
+	n.feedInterval = DefaultFeedInterval
+	n.categories = make(map[Category]bool)
+
+	for _, category := range []Category{
+		CategoryTrack,
+		CategoryDownload,
+		CategoryFeed,
+	} {
+		n.categories[category] = k.Bool("notifications." + string(category))
+	}
+
+	if interval := k.String("notifications.feed-interval"); interval != "" {
+		duration, err := time.ParseDuration(interval)
+		if err != nil || duration < time.Minute {
+			return fmt.Errorf("Config: Invalid feed notification interval %q", interval)
+		}
+
+		n.feedInterval = duration
+	}
+
+	return nil
+}
+
+// Generate generates the notifications configuration.
+func (n *Notifier) Generate(k *koanf.Koanf) (interface{}, error) {
+	notifyMap := k.Get("notifications")
+	if notifyMap == nil {
+		notifyMap = map[string]interface{}{
+			"command":                "",
+			"feed-interval":          DefaultFeedInterval.String(),
+			string(CategoryTrack):    false,
+			string(CategoryDownload): false,
+			string(CategoryFeed):     false,
+		}
+	}
+
+	return notifyMap, nil
+}
+
+// SetErrorHandler sets the function which is called when a notification cannot be sent.
+func SetErrorHandler(handler func(err error)) {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+
+	notifier.errorHandler = handler
+}
+
+// Enabled returns whether notifications are enabled for the category.
+func Enabled(category Category) bool {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+
+	return notifier.categories[category]
+}
+
+// FeedInterval returns the interval at which the feed is checked for new items.
+func FeedInterval() time.Duration {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+
+	return notifier.feedInterval
+}
+
+// Send sends the notification in the background, if its category is enabled.
+// The notification is sent via the org.freedesktop.Notifications D-Bus service,
+// or via the configured command if the service is unavailable. If the icon is
+// a URL, it is downloaded before the notification is sent. Track notifications
+// replace the previous track notification, instead of being stacked.
+func Send(notification Notification) {
+	if !Enabled(notification.Category) {
+		return
+	}
+
+	go func() {
+		if strings.HasPrefix(notification.Icon, "http") {
+			notification.Icon = fetchIcon(notification.Icon)
+		}
+
+		var replaces uint32
+
+		track := notification.Category == CategoryTrack
+
+		notifier.mutex.Lock()
+		if track {
+			replaces = notifier.replaces
+		}
+		command, handler := notifier.command, notifier.errorHandler
+		notifier.mutex.Unlock()
+
+		id, err := sendDBus(notification, replaces)
+		if err == nil {
+			if track {
+				notifier.mutex.Lock()
+				notifier.replaces = id
+				notifier.mutex.Unlock()
+			}
+
+			return
+		}
+
+		if command != "" {
+			err = sendCommand(notification, command)
+		}
+		if err != nil && handler != nil {
+			handler(fmt.Errorf("Notify: Cannot send notification: %w", err))
+		}
+	}()
+}
+
+// sendCommand runs the configured command with the notification's
+// details as environment variables.
+func sendCommand(notification Notification, command string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd := platform.ShellCommand(ctx, command)
+	cmd.Env = append(os.Environ(),
+		"INVIDTUI_NOTIFY_CATEGORY="+string(notification.Category),
+		"INVIDTUI_NOTIFY_TITLE="+notification.Title,
+		"INVIDTUI_NOTIFY_BODY="+notification.Body,
+		"INVIDTUI_NOTIFY_ICON="+notification.Icon,
+	)
+
+	return cmd.Run()
+}
+
+// fetchIcon downloads the icon into the notification icon cache, and returns
+// its path. Each icon is cached under a name derived from its URL, so that
+// notifications shown at the same time do not share an icon file.
+// An empty path is returned on failure.
+func fetchIcon(uri string) string {
+	name := fmt.Sprintf("%x.jpg", sha256.Sum256([]byte(uri)))
+
+	path, err := cmd.GetPath(filepath.Join("notifications", name), struct{}{})
+	if err == nil {
+		return path
+	}
+	if path == "" {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := client.GetURL(ctx, uri)
+	if err != nil {
+		return ""
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return ""
+	}
+
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, data, 0644); err != nil {
+		return ""
+	}
+	if err := os.Rename(temp, path); err != nil {
+		return ""
+	}
+
+	return path
+}
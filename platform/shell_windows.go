@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package platform
+
+import (
+	"context"
+	"os/exec"
+)
+
+// ShellCommand returns a command which runs the provided command line within the shell.
+func ShellCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/C", command)
+}
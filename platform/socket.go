@@ -3,7 +3,43 @@
 
 package platform
 
+import (
+	"net"
+	"os"
+	"path/filepath"
+)
+
 // Socket returns the socket path.
 func Socket(sock string) string {
 	return sock
 }
+
+// ListenSocket listens on a unix socket at the provided path, which only the
+// current user can access. The socket is created within a private directory,
+// and moved to the path once its permissions are set, so that it cannot be
+// connected to beforehand.
+func ListenSocket(path string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".socket-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	temp := filepath.Join(dir, filepath.Base(path))
+
+	listener, err := net.Listen("unix", temp)
+	if err != nil {
+		return nil, err
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	if err = os.Chmod(temp, 0600); err == nil {
+		err = os.Rename(temp, path)
+	}
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
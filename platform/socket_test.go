@@ -0,0 +1,66 @@
+//go:build !windows
+// +build !windows
+
+package platform
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestListenSocket(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.sock")
+
+	umask := syscall.Umask(0022)
+	defer syscall.Umask(umask)
+
+	listener, err := ListenSocket(path)
+	if err != nil {
+		t.Fatalf("ListenSocket: %v", err)
+	}
+	defer listener.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Mode()&os.ModeSocket == 0 || info.Mode().Perm() != 0600 {
+		t.Errorf("Mode = %v, want a socket with 0600 permissions", info.Mode())
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Directory contains %d entries, want only the socket", len(entries))
+	}
+
+	if current := syscall.Umask(0022); current != 0022 {
+		t.Errorf("Umask = %#o, want %#o", current, 0022)
+	}
+
+	accepted := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			conn.Close()
+		}
+
+		accepted <- err
+	}()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	conn.Close()
+
+	if err := <-accepted; err != nil {
+		t.Errorf("Accept: %v", err)
+	}
+}
@@ -3,7 +3,14 @@
 
 package platform
 
+import "net"
+
 // Socket returns the socket path.
 func Socket(sock string) string {
 	return `\\.\pipe\invidtui-socket`
 }
+
+// ListenSocket listens on a unix socket at the provided path.
+func ListenSocket(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
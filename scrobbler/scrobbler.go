@@ -0,0 +1,552 @@
+package scrobbler
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/knadh/koanf/v2"
+)
+
+// Scrobbler describes the scrobbler.
+type Scrobbler struct {
+	listenbrainz ListenBrainz
+	lastfm       LastFM
+
+	track *scrobbleTrack
+
+	queue     []Scrobble
+	queuePath string
+
+	client *http.Client
+
+	errorHandler func(err error)
+
+	mutex sync.Mutex
+}
+
+// Playback describes the playback state of the current track.
+type Playback struct {
+	VideoID, Title, Author string
+	Duration               int64
+
+	Playing, Stopped bool
+}
+
+// ListenBrainz describes the ListenBrainz service configuration.
+type ListenBrainz struct {
+	URL, Token string
+}
+
+// LastFM describes the Last.fm service configuration.
+type LastFM struct {
+	URL, APIKey, Secret, SessionKey string
+}
+
+// Scrobble describes a listen which is submitted to a service.
+type Scrobble struct {
+	Service    string `json:"service"`
+	VideoID    string `json:"videoId"`
+	Artist     string `json:"artist"`
+	Track      string `json:"track"`
+	Duration   int64  `json:"duration"`
+	ListenedAt int64  `json:"listenedAt"`
+}
+
+// scrobbleTrack describes the currently playing track.
+type scrobbleTrack struct {
+	Scrobble
+
+	played    time.Duration
+	submitted bool
+}
+
+// scrobbleError describes a submission error, and whether
+// the submission can be retried later.
+type scrobbleError struct {
+	retry bool
+	err   error
+}
+
+// The scrobbling services.
+const (
+	ServiceListenBrainz = "listenbrainz"
+	ServiceLastFM       = "lastfm"
+)
+
+// The default service URLs.
+const (
+	DefaultListenBrainzURL = "https://api.listenbrainz.org"
+	DefaultLastFMURL       = "https://ws.audioscrobbler.com/2.0/"
+)
+
+var (
+	scrobbler Scrobbler
+
+	titleSuffix = regexp.MustCompile(
+		`(?i)\s*[\(\[][^\)\]]*(official|video|audio|lyrics?|visuali[sz]er|hd|hq|4k|mv|m/v|full album)[^\)\]]*[\)\]]`,
+	)
+	titleSeparator = regexp.MustCompile(`\s+[-–—~|]\s+`)
+)
+
+// GetConfigHandler returns the scrobbler configuration handler.
+func GetConfigHandler() *Scrobbler {
+	return &scrobbler
+}
+
+// Parse parses the scrobbler configuration.
+func (s *Scrobbler) Parse(k *koanf.Koanf, dir string) error {
+	s.listenbrainz = ListenBrainz{
+		URL:   k.String("scrobble.listenbrainz.url"),
+		Token: k.String("scrobble.listenbrainz.token"),
+	}
+	if s.listenbrainz.URL == "" {
+		s.listenbrainz.URL = DefaultListenBrainzURL
+	}
+
+	s.lastfm = LastFM{
+		URL:        k.String("scrobble.lastfm.url"),
+		APIKey:     k.String("scrobble.lastfm.api-key"),
+		Secret:     k.String("scrobble.lastfm.secret"),
+		SessionKey: k.String("scrobble.lastfm.session-key"),
+	}
+	if s.lastfm.URL == "" {
+		s.lastfm.URL = DefaultLastFMURL
+	}
+
+	for _, uri := range []string{s.listenbrainz.URL, s.lastfm.URL} {
+		if _, err := url.ParseRequestURI(uri); err != nil {
+			return fmt.Errorf("Config: Invalid scrobbler URL %q", uri)
+		}
+	}
+
+	return nil
+}
+
+// Generate generates the scrobbler configuration.
+func (s *Scrobbler) Generate(k *koanf.Koanf) (interface{}, error) {
+	scrobbleMap := k.Get("scrobble")
+	if scrobbleMap == nil {
+		scrobbleMap = map[string]interface{}{
+			"listenbrainz": map[string]interface{}{
+				"url":   DefaultListenBrainzURL,
+				"token": "",
+			},
+			"lastfm": map[string]interface{}{
+				"url":         DefaultLastFMURL,
+				"api-key":     "",
+				"secret":      "",
+				"session-key": "",
+			},
+		}
+	}
+
+	return scrobbleMap, nil
+}
+
+// SetErrorHandler sets the function which is called when a listen cannot be submitted.
+func SetErrorHandler(handler func(err error)) {
+	scrobbler.mutex.Lock()
+	defer scrobbler.mutex.Unlock()
+
+	scrobbler.errorHandler = handler
+}
+
+// Start starts the scrobbler, if any service is configured. Submissions which
+// fail are stored on disk, and retried periodically until the context is done.
+func Start(ctx context.Context) error {
+	if scrobbler.services() == nil {
+		return nil
+	}
+
+	path, err := cmd.GetPath("scrobbles.json")
+	if err != nil {
+		return fmt.Errorf("Scrobbler: Cannot open the queue file: %w", err)
+	}
+
+	scrobbler.mutex.Lock()
+	scrobbler.queuePath = path
+	scrobbler.client = &http.Client{Timeout: 30 * time.Second}
+	scrobbler.loadQueue()
+	scrobbler.mutex.Unlock()
+
+	go scrobbler.retry(ctx)
+
+	return nil
+}
+
+// Update updates the currently playing track according to the playback state,
+// and submits it once half of it, or 4 minutes of it, have been played.
+// It should be called with the previous and the current playback state whenever
+// the state changes, which is every second while the track is playing.
+func Update(previous, current Playback) {
+	scrobbler.update(previous, current)
+}
+
+// update updates the currently playing track according to the playback state.
+func (s *Scrobbler) update(previous, current Playback) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.queuePath == "" {
+		return
+	}
+
+	if current.Stopped {
+		s.track = nil
+		return
+	}
+
+	if s.track == nil || s.track.VideoID != current.VideoID {
+		artist, track := ParseTrack(current.Title, current.Author)
+
+		s.track = &scrobbleTrack{
+			Scrobble: Scrobble{
+				VideoID:    current.VideoID,
+				Artist:     artist,
+				Track:      track,
+				Duration:   current.Duration,
+				ListenedAt: time.Now().Unix(),
+			},
+		}
+
+		go s.submit(s.track.Scrobble, true)
+
+		return
+	}
+
+	if current.Duration > 0 {
+		s.track.Duration = current.Duration
+	}
+
+	if previous.Playing && current.Playing && previous.VideoID == current.VideoID {
+		s.track.played += time.Second
+	}
+
+	threshold := time.Duration(s.track.Duration) * time.Second / 2
+	if threshold > 4*time.Minute {
+		threshold = 4 * time.Minute
+	}
+
+	if s.track.submitted || s.track.Duration <= 30 || s.track.played < threshold {
+		return
+	}
+
+	s.track.submitted = true
+	go s.submit(s.track.Scrobble, false)
+}
+
+// submit submits the track to all the configured services. If 'nowPlaying' is set,
+// the track is submitted as currently playing, otherwise it is submitted as a listen,
+// and is added to the queue if it could not be submitted.
+func (s *Scrobbler) submit(scrobble Scrobble, nowPlaying bool) {
+	for _, service := range s.services() {
+		scrobble.Service = service
+
+		err := s.send(scrobble, nowPlaying)
+		if err == nil || nowPlaying {
+			continue
+		}
+
+		var serr scrobbleError
+		if errors.As(err, &serr) && !serr.retry {
+			s.mutex.Lock()
+			handler := s.errorHandler
+			s.mutex.Unlock()
+
+			if handler != nil {
+				handler(err)
+			}
+
+			continue
+		}
+
+		s.mutex.Lock()
+		s.queue = append(s.queue, scrobble)
+		s.saveQueue()
+		s.mutex.Unlock()
+	}
+}
+
+// retry periodically resubmits the queued listens, until the context is done.
+func (s *Scrobbler) retry(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		s.mutex.Lock()
+		queue := s.queue
+		s.queue = nil
+		s.mutex.Unlock()
+
+		var failed []Scrobble
+		for _, scrobble := range queue {
+			err := s.send(scrobble, false)
+
+			var serr scrobbleError
+			if err != nil && (!errors.As(err, &serr) || serr.retry) {
+				failed = append(failed, scrobble)
+			}
+		}
+
+		if queue != nil {
+			s.mutex.Lock()
+			s.queue = append(failed, s.queue...)
+			s.saveQueue()
+			s.mutex.Unlock()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// send submits the track to its service.
+func (s *Scrobbler) send(scrobble Scrobble, nowPlaying bool) error {
+	switch scrobble.Service {
+	case ServiceListenBrainz:
+		return s.sendListenBrainz(scrobble, nowPlaying)
+
+	case ServiceLastFM:
+		return s.sendLastFM(scrobble, nowPlaying)
+	}
+
+	return nil
+}
+
+// sendListenBrainz submits the track to a ListenBrainz-compatible server.
+func (s *Scrobbler) sendListenBrainz(scrobble Scrobble, nowPlaying bool) error {
+	listen := map[string]interface{}{
+		"track_metadata": map[string]interface{}{
+			"artist_name": scrobble.Artist,
+			"track_name":  scrobble.Track,
+			"additional_info": map[string]interface{}{
+				"duration_ms":       scrobble.Duration * 1000,
+				"origin_url":        "https://www.youtube.com/watch?v=" + scrobble.VideoID,
+				"media_player":      "invidtui",
+				"submission_client": "invidtui",
+			},
+		},
+	}
+
+	listenType := "playing_now"
+	if !nowPlaying {
+		listenType = "single"
+		listen["listened_at"] = scrobble.ListenedAt
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"listen_type": listenType,
+		"payload":     []interface{}{listen},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		strings.TrimSuffix(s.listenbrainz.URL, "/")+"/1/submit-listens",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Token "+s.listenbrainz.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	return s.do(req, ServiceListenBrainz, func(data []byte) error {
+		return nil
+	})
+}
+
+// sendLastFM submits the track to a Last.fm-compatible server.
+func (s *Scrobbler) sendLastFM(scrobble Scrobble, nowPlaying bool) error {
+	params := map[string]string{
+		"method":   "track.scrobble",
+		"artist":   scrobble.Artist,
+		"track":    scrobble.Track,
+		"duration": strconv.FormatInt(scrobble.Duration, 10),
+		"api_key":  s.lastfm.APIKey,
+		"sk":       s.lastfm.SessionKey,
+	}
+	if nowPlaying {
+		params["method"] = "track.updateNowPlaying"
+	} else {
+		params["timestamp"] = strconv.FormatInt(scrobble.ListenedAt, 10)
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var signature strings.Builder
+	form := url.Values{}
+	for _, key := range keys {
+		signature.WriteString(key + params[key])
+		form.Set(key, params[key])
+	}
+	signature.WriteString(s.lastfm.Secret)
+
+	sum := md5.Sum([]byte(signature.String()))
+	form.Set("api_sig", hex.EncodeToString(sum[:]))
+	form.Set("format", "json")
+
+	req, err := http.NewRequest(http.MethodPost, s.lastfm.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return s.do(req, ServiceLastFM, func(data []byte) error {
+		var response struct {
+			Error   int    `json:"error"`
+			Message string `json:"message"`
+		}
+
+		if json.Unmarshal(data, &response) != nil || response.Error == 0 {
+			return nil
+		}
+
+		return scrobbleError{
+			retry: response.Error == 11 || response.Error == 16 || response.Error == 29,
+			err:   errors.New(response.Message),
+		}
+	})
+}
+
+// do sends the request. Network errors, server errors and rate limits
+// can be retried, while any other error cannot.
+func (s *Scrobbler) do(req *http.Request, service string, check func(data []byte) error) error {
+	req.Header.Set("User-Agent", "invidtui")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return scrobbleError{true, fmt.Errorf("Scrobbler: %s: %w", service, err)}
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return scrobbleError{true, fmt.Errorf("Scrobbler: %s: %w", service, err)}
+	}
+
+	if res.StatusCode >= 300 {
+		var serr scrobbleError
+
+		if err := check(data); errors.As(err, &serr) {
+			serr.err = fmt.Errorf("Scrobbler: %s: %w", service, serr.err)
+			return serr
+		}
+
+		return scrobbleError{
+			retry: res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests,
+			err:   fmt.Errorf("Scrobbler: %s: %s", service, res.Status),
+		}
+	}
+
+	if err := check(data); err != nil {
+		var serr scrobbleError
+		if errors.As(err, &serr) {
+			serr.err = fmt.Errorf("Scrobbler: %s: %w", service, serr.err)
+			return serr
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// services returns the configured services.
+func (s *Scrobbler) services() []string {
+	var services []string
+
+	if s.listenbrainz.Token != "" {
+		services = append(services, ServiceListenBrainz)
+	}
+	if s.lastfm.APIKey != "" && s.lastfm.Secret != "" && s.lastfm.SessionKey != "" {
+		services = append(services, ServiceLastFM)
+	}
+
+	return services
+}
+
+// loadQueue loads the queued listens from disk.
+func (s *Scrobbler) loadQueue() {
+	data, err := os.ReadFile(s.queuePath)
+	if err != nil || len(bytes.TrimSpace(data)) == 0 {
+		return
+	}
+
+	json.Unmarshal(data, &s.queue)
+}
+
+// saveQueue saves the queued listens to disk.
+func (s *Scrobbler) saveQueue() {
+	queue := s.queue
+	if queue == nil {
+		queue = []Scrobble{}
+	}
+
+	data, err := json.Marshal(queue)
+	if err != nil {
+		return
+	}
+
+	os.WriteFile(s.queuePath, data, 0644)
+}
+
+// Error returns the error message.
+func (e scrobbleError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e scrobbleError) Unwrap() error {
+	return e.err
+}
+
+// ParseTrack parses the artist and track name from the video's title and author.
+// Titles of the form "Artist - Track" are split, and common suffixes such as
+// "(Official Video)" are removed. Otherwise, the author is used as the artist,
+// without the " - Topic" suffix of auto-generated channels.
+func ParseTrack(title, author string) (string, string) {
+	artist := strings.TrimSpace(strings.TrimSuffix(author, " - Topic"))
+	artist = strings.TrimSpace(strings.TrimSuffix(artist, "VEVO"))
+
+	track := strings.TrimSpace(titleSuffix.ReplaceAllString(title, ""))
+
+	if parts := titleSeparator.Split(track, 2); len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+		artist, track = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	}
+
+	track = strings.Trim(track, `"'“”`)
+	if track == "" {
+		track = title
+	}
+
+	return artist, track
+}
@@ -0,0 +1,31 @@
+package scrobbler
+
+import "testing"
+
+func TestParseTrack(t *testing.T) {
+	tests := []struct {
+		title, author string
+		artist, track string
+	}{
+		{"Artist - Track", "Channel", "Artist", "Track"},
+		{"Artist – Track (Official Video)", "ArtistVEVO", "Artist", "Track"},
+		{"Artist - Track [Official Audio] (Lyrics)", "Channel", "Artist", "Track"},
+		{"Artist | Track (HD)", "Channel", "Artist", "Track"},
+		{`Artist - "Track"`, "Channel", "Artist", "Track"},
+		{"Track", "Artist - Topic", "Artist", "Track"},
+		{"Track (Visualizer)", "ArtistVEVO", "Artist", "Track"},
+		{"Track (Remix)", "Channel", "Channel", "Track (Remix)"},
+		{"Track-With-Dashes", "Channel", "Channel", "Track-With-Dashes"},
+		{"(Official Video)", "Channel", "Channel", "(Official Video)"},
+	}
+
+	for _, test := range tests {
+		artist, track := ParseTrack(test.title, test.author)
+		if artist != test.artist || track != test.track {
+			t.Errorf(
+				"ParseTrack(%q, %q) = %q, %q, want %q, %q",
+				test.title, test.author, artist, track, test.artist, test.track,
+			)
+		}
+	}
+}
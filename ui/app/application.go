@@ -4,7 +4,9 @@ import (
 	"context"
 	"sync"
 
+	"github.com/darkhz/invidtui/cmd"
 	"github.com/darkhz/invidtui/platform"
+	"github.com/darkhz/invidtui/ui/keybinding"
 	"github.com/darkhz/invidtui/ui/theme"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
@@ -40,9 +42,16 @@ var UI Application
 
 // Setup sets up the application
 func Setup() error {
-	screen, err := tcell.NewScreen()
-	if err != nil {
-		return err
+	var screen tcell.Screen
+	var err error
+
+	if cmd.IsOptionEnabled("daemon") {
+		screen = NewDetachedScreen()
+	} else {
+		screen, err = tcell.NewScreen()
+		if err != nil {
+			return err
+		}
 	}
 
 	property := theme.ThemeProperty{
@@ -50,8 +59,6 @@ func Setup() error {
 		Item:    theme.ThemeBackground,
 	}
 
-	box := theme.NewBox(property)
-
 	UI.Status.Setup()
 
 	UI.Menu, UI.Tabs =
@@ -67,21 +74,13 @@ func Setup() error {
 		AddItem(UI.Menu, 0, 1, false).
 		AddItem(UI.Tabs, 0, 1, false)
 
-	UI.Pages = theme.NewPages(property)
-	UI.Pages.SetChangedFunc(func() {
-		MenuExit()
-	})
+	UI.Pages = newPages(property)
 
-	UI.Region = theme.NewFlex(property).
-		AddItem(UI.Pages, 0, 1, true)
+	UI.Region = theme.NewFlex(property)
 
 	UI.Layout = theme.NewFlex(property).
-		SetDirection(tview.FlexRow).
-		AddItem(UI.MenuLayout, 1, 0, false).
-		AddItem(box, 1, 0, false).
-		AddItem(UI.Region, 0, 10, false).
-		AddItem(box, 1, 0, false).
-		AddItem(UI.Status.Pages, 1, 0, false)
+		SetDirection(tview.FlexRow)
+	setupLayout(property)
 
 	UI.Area = theme.NewPages(property)
 	UI.Area.AddPage("ui", UI.Layout, true, true)
@@ -100,6 +99,7 @@ func Setup() error {
 	UI.Application = tview.NewApplication()
 	UI.Application.SetScreen(UI.Screen)
 	UI.SetAfterDrawFunc(func(screen tcell.Screen) {
+		resizeLayout(screen)
 		UI.resize(screen)
 		suspend(screen)
 	})
@@ -108,9 +108,33 @@ func Setup() error {
 		p.Draw(UI.Screen)
 	})
 
+	setupMouse()
+
 	return nil
 }
 
+// NewDetachedScreen returns a screen which is not attached to any terminal.
+// It is used to run the application in the background, in daemon mode.
+func NewDetachedScreen() tcell.Screen {
+	return tcell.NewSimulationScreen("UTF-8")
+}
+
+// SetScreen replaces the screen that the application is drawn on.
+func SetScreen(screen tcell.Screen) {
+	UI.lock.Lock()
+	defer UI.lock.Unlock()
+
+	select {
+	case <-UI.Closed.Done():
+		return
+
+	default:
+	}
+
+	UI.Screen = screen
+	UI.Application.SetScreen(screen)
+}
+
 // SetPrimaryFocus sets the focus to the appropriate primitive.
 func SetPrimaryFocus() {
 	if pg, _ := UI.Status.GetFrontPage(); pg == "input" {
@@ -132,8 +156,38 @@ func SetResizeHandler(resize func(screen tcell.Screen)) {
 }
 
 // SetGlobalKeybindings sets the keybindings for the app.
+// Key sequences and count prefixes are parsed before the keybindings
+// are applied, and keys entered with a count are repeated.
 func SetGlobalKeybindings(kb func(event *tcell.EventKey) *tcell.EventKey) {
-	UI.SetInputCapture(kb)
+	var pending bool
+
+	UI.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if _, ok := UI.GetFocus().(*tview.InputField); ok {
+			return kb(event)
+		}
+
+		ev, count := keybinding.ParseSequence(event, menuArea.context)
+		if text := keybinding.PendingSequence(); text != "" {
+			pending = true
+			ShowInfo("Keys: "+text, true)
+		} else if pending {
+			pending = false
+			ShowInfo("", false)
+		}
+		if ev == nil {
+			return nil
+		}
+
+		for i := 1; i < count; i++ {
+			if e := kb(ev); e != nil && UI.Area.HasFocus() {
+				UI.Area.InputHandler()(e, func(p tview.Primitive) {
+					UI.SetFocus(p)
+				})
+			}
+		}
+
+		return kb(ev)
+	})
 }
 
 // DrawPrimitives draws the primitives onto the screen.
@@ -0,0 +1,192 @@
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/darkhz/invidtui/ui/keybinding"
+	"github.com/darkhz/invidtui/ui/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Help describes the layout of the keybindings help popup.
+type Help struct {
+	context keybinding.KeyContext
+
+	modal *Modal
+	flex  *tview.Flex
+	table *tview.Table
+	input *tview.InputField
+}
+
+var help Help
+
+// ShowHelp shows a popup with the keybindings of the currently focused
+// context, along with the global keybindings.
+func ShowHelp() {
+	if help.modal != nil {
+		if help.modal.Open {
+			return
+		}
+
+		goto Render
+	}
+
+	setupHelp()
+
+Render:
+	help.context = menuArea.context
+	help.input.SetText("")
+	help.modal.Show(false)
+
+	helpFilter("")
+}
+
+// setupHelp sets up the keybindings help popup.
+func setupHelp() {
+	property := theme.ThemeProperty{
+		Context: theme.ThemeContextHelp,
+		Item:    theme.ThemePopupBackground,
+	}
+
+	help.table = theme.NewTable(property)
+	help.table.SetSelectable(true, false)
+	help.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch keybinding.KeyOperation(event, keybinding.KeyContextCommon) {
+		case keybinding.KeyQuery:
+			UI.SetFocus(help.input)
+			return nil
+
+		case keybinding.KeyClose:
+			help.modal.Exit(false)
+		}
+
+		return event
+	})
+
+	help.input = theme.NewInputField(property, "Filter:")
+	help.input.SetChangedFunc(helpFilter)
+	help.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch keybinding.KeyOperation(event, keybinding.KeyContextCommon) {
+		case keybinding.KeySelect, keybinding.KeyClose:
+			UI.SetFocus(help.table)
+		}
+
+		return event
+	})
+
+	help.flex = theme.NewFlex(property).
+		SetDirection(tview.FlexRow).
+		AddItem(help.table, 0, 10, true).
+		AddItem(HorizontalLine(property.SetItem(theme.ThemePopupBorder)), 1, 0, false).
+		AddItem(help.input, 1, 0, false)
+
+	help.modal = NewModal("help", "Keybindings", help.flex, 40, 80, property)
+}
+
+// helpFilter lists the keybindings which match the provided text,
+// grouped by their context.
+//
+//gocyclo:ignore
+func helpFilter(text string) {
+	var row int
+
+	text = strings.ToLower(text)
+	groups := make(map[keybinding.KeyContext][]keybinding.Key)
+
+	for key, data := range keybinding.OperationKeys {
+		if data.Context != help.context && data.Context != keybinding.KeyContextCommon && !data.Global {
+			continue
+		}
+
+		if text != "" &&
+			!strings.Contains(strings.ToLower(data.Title), text) &&
+			!strings.Contains(strings.ToLower(keybinding.BindingName(data)), text) &&
+			!strings.Contains(strings.ToLower(string(key)), text) {
+			continue
+		}
+
+		groups[data.Context] = append(groups[data.Context], key)
+	}
+
+	contexts := make([]keybinding.KeyContext, 0, len(groups))
+	for context := range groups {
+		contexts = append(contexts, context)
+	}
+	sort.Slice(contexts, func(i, j int) bool {
+		for _, context := range []keybinding.KeyContext{help.context, keybinding.KeyContextCommon} {
+			if contexts[i] == context || contexts[j] == context {
+				return contexts[i] == context
+			}
+		}
+
+		return contexts[i] < contexts[j]
+	})
+
+	help.table.Clear()
+
+	for _, context := range contexts {
+		keys := groups[context]
+		sort.Slice(keys, func(i, j int) bool {
+			return keybinding.OperationData(keys[i]).Title < keybinding.OperationData(keys[j]).Title
+		})
+
+		help.table.SetCell(row, 0, theme.NewTableCell(
+			theme.ThemeContextHelp,
+			theme.ThemeName,
+			string(context),
+		).
+			SetSelectable(false),
+		)
+		row++
+
+		for _, key := range keys {
+			var status string
+
+			data := keybinding.OperationData(key)
+			if keybinding.Unbound(data) {
+				status = "unbound"
+			} else if conflicts := keybinding.Conflicts(key); conflicts != nil {
+				names := make([]string, len(conflicts))
+				for i, conflict := range conflicts {
+					names[i] = string(conflict)
+				}
+				sort.Strings(names)
+
+				status = "conflicts with " + strings.Join(names, ", ")
+			}
+
+			help.table.SetCell(row, 0, theme.NewTableCell(
+				theme.ThemeContextHelp,
+				theme.ThemeDescription,
+				tview.Escape(data.Title),
+			).
+				SetExpansion(1),
+			)
+
+			help.table.SetCell(row, 1, theme.NewTableCell(
+				theme.ThemeContextHelp,
+				theme.ThemeKeybinding,
+				tview.Escape(keybinding.BindingName(data)),
+			).
+				SetAlign(tview.AlignRight),
+			)
+
+			help.table.SetCell(row, 2, theme.NewTableCell(
+				theme.ThemeContextHelp,
+				theme.ThemeTagError,
+				status,
+			).
+				SetSelectable(true),
+			)
+
+			row++
+		}
+	}
+
+	help.table.ScrollToBeginning()
+	help.table.Select(1, 0)
+
+	ResizeModal()
+}
@@ -0,0 +1,297 @@
+package app
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Layout describes the arrangement of the player, the side pane
+// and the split view.
+type Layout struct {
+	player, pane tview.Primitive
+
+	playerTop, split       bool
+	paneWidth, smallHeight int
+	active                 int
+
+	compact, small atomic.Bool
+
+	pages       [2]*tview.Pages
+	paneChanged func()
+
+	box                    *tview.Box
+	property, paneProperty theme.ThemeProperty
+}
+
+const (
+	// PaneWidthStep is the amount by which the side pane is resized.
+	PaneWidthStep = 5
+
+	minPaneWidth = 10
+	maxPaneWidth = 90
+)
+
+var layout Layout
+
+// setupLayout loads the layout settings from the configuration.
+func setupLayout(property theme.ThemeProperty) {
+	layout.property = property
+	layout.pages[0] = UI.Pages
+
+	layout.box = theme.NewBox(property)
+	layout.playerTop = cmd.GetOptionValue("player-position") == "top"
+	layout.compact.Store(cmd.IsOptionEnabled("compact-player"))
+
+	layout.paneWidth, _ = strconv.Atoi(cmd.GetOptionValue("side-pane-width"))
+	layout.smallHeight, _ = strconv.Atoi(cmd.GetOptionValue("small-terminal-height"))
+
+	arrangeLayout()
+	arrangeRegion()
+}
+
+// ShowPlayer shows the player within the layout.
+func ShowPlayer(player tview.Primitive) {
+	layout.player = player
+	arrangeLayout()
+}
+
+// HidePlayer removes the player from the layout.
+func HidePlayer() {
+	layout.player = nil
+	arrangeLayout()
+}
+
+// IsCompactPlayer returns whether the player is displayed in a single line.
+func IsCompactPlayer() bool {
+	return layout.compact.Load() || layout.small.Load()
+}
+
+// ToggleCompactPlayer toggles the single-line display of the player.
+func ToggleCompactPlayer() {
+	layout.compact.Store(!layout.compact.Load())
+	arrangeLayout()
+}
+
+// TogglePlayerPosition moves the player to the top or bottom of the screen.
+func TogglePlayerPosition() {
+	layout.playerTop = !layout.playerTop
+	arrangeLayout()
+}
+
+// SetSidePane sets the primitive to display alongside the pages.
+// If pane is nil, the side pane is removed.
+func SetSidePane(pane tview.Primitive, property theme.ThemeProperty) {
+	if layout.pane != nil && layout.pane != pane && layout.pane.HasFocus() {
+		defer SetPrimaryFocus()
+	}
+
+	layout.pane = pane
+	layout.paneProperty = property
+
+	arrangeRegion()
+}
+
+// SidePane returns the primitive displayed within the side pane.
+func SidePane() tview.Primitive {
+	return layout.pane
+}
+
+// ResizeSidePane increases or decreases the width of the side pane
+// by the provided percentage of the screen width.
+func ResizeSidePane(delta int) {
+	if layout.pane == nil {
+		ShowInfo("No side pane is shown", false)
+		return
+	}
+
+	layout.paneWidth += delta
+	if layout.paneWidth < minPaneWidth {
+		layout.paneWidth = minPaneWidth
+	}
+	if layout.paneWidth > maxPaneWidth {
+		layout.paneWidth = maxPaneWidth
+	}
+
+	arrangeRegion()
+}
+
+// SetPaneChangedFunc sets the handler to run when the focused split pane changes.
+func SetPaneChangedFunc(changed func()) {
+	layout.paneChanged = changed
+}
+
+// IsSplit returns whether the page area is split into two panes.
+func IsSplit() bool {
+	return layout.split
+}
+
+// ActivePane returns the index of the focused split pane.
+func ActivePane() int {
+	return layout.active
+}
+
+// SetSplit splits the page area into two panes, and focuses the new pane.
+// If split is false, the unfocused pane is removed.
+func SetSplit(split bool) {
+	if split == layout.split {
+		return
+	}
+
+	layout.split = split
+
+	if split {
+		layout.pages[1] = newPages(layout.property)
+		layout.active = 1
+	} else {
+		layout.pages[0], layout.pages[1] = layout.pages[layout.active], nil
+		layout.active = 0
+	}
+
+	UI.Pages = layout.pages[layout.active]
+
+	arrangeRegion()
+	SetPrimaryFocus()
+}
+
+// UnfocusedPages returns the pages of the unfocused split pane.
+// If the page area is not split, nil is returned.
+func UnfocusedPages() *tview.Pages {
+	if !layout.split {
+		return nil
+	}
+
+	return layout.pages[1-layout.active]
+}
+
+// FocusPane focuses the split pane at the provided index.
+func FocusPane(index int) {
+	if !layout.split || index == layout.active || index < 0 || index > 1 {
+		return
+	}
+
+	layout.active = index
+	UI.Pages = layout.pages[index]
+
+	if layout.paneChanged != nil {
+		layout.paneChanged()
+	}
+
+	SetPrimaryFocus()
+}
+
+// SwitchPane focuses the other split pane.
+func SwitchPane() {
+	if !layout.split {
+		ShowInfo("View is not split", false)
+		return
+	}
+
+	FocusPane(1 - layout.active)
+}
+
+// newPages returns the pages to display the views in.
+func newPages(property theme.ThemeProperty) *tview.Pages {
+	pages := theme.NewPages(property)
+	pages.SetChangedFunc(func() {
+		MenuExit()
+	})
+
+	return pages
+}
+
+// resizeLayout collapses the player if the screen height is
+// lower than the configured small terminal height.
+func resizeLayout(screen tcell.Screen) {
+	_, height := screen.Size()
+
+	if small := height < layout.smallHeight; small != layout.small.Load() {
+		layout.small.Store(small)
+		arrangeLayout()
+
+		go UI.Draw()
+	}
+}
+
+// arrangeLayout arranges the menu, player, pages and status bar.
+func arrangeLayout() {
+	height := 2
+	if IsCompactPlayer() {
+		height = 1
+	}
+
+	UI.Layout.Clear().
+		AddItem(UI.MenuLayout, 1, 0, false).
+		AddItem(layout.box, 1, 0, false)
+
+	if layout.player != nil && layout.playerTop {
+		UI.Layout.
+			AddItem(layout.player, height, 0, false).
+			AddItem(layout.box, 1, 0, false)
+	}
+
+	UI.Layout.
+		AddItem(UI.Region, 0, 10, false).
+		AddItem(layout.box, 1, 0, false).
+		AddItem(UI.Status.Pages, 1, 0, false)
+
+	if layout.player != nil && !layout.playerTop {
+		UI.Layout.AddItem(layout.player, height, 0, false)
+	}
+
+	for _, modal := range modals {
+		modal.pageHeight = 0
+	}
+
+	ResizeModal()
+}
+
+// arrangeRegion arranges the side pane and the split panes.
+func arrangeRegion() {
+	width := 100 - layout.paneWidth
+
+	UI.Region.Clear()
+
+	if layout.pane != nil {
+		box := theme.NewBox(layout.paneProperty)
+		vbox := VerticalLine(layout.paneProperty.SetItem(theme.ThemeBorder))
+
+		UI.Region.
+			AddItem(layout.pane, 0, layout.paneWidth, false).
+			AddItem(box, 1, 0, false).
+			AddItem(vbox, 1, 0, false).
+			AddItem(box, 1, 0, false)
+	}
+
+	if !layout.split {
+		UI.Region.AddItem(layout.pages[0], 0, width, true)
+		return
+	}
+
+	vbox := VerticalLine(layout.property.SetItem(theme.ThemeBorder))
+
+	UI.Region.
+		AddItem(layout.pages[0], 0, width/2, layout.active == 0).
+		AddItem(layout.box, 1, 0, false).
+		AddItem(vbox, 1, 0, false).
+		AddItem(layout.box, 1, 0, false).
+		AddItem(layout.pages[1], 0, width/2, layout.active == 1)
+}
+
+// bottomPlayerHeight returns the height of the player
+// if it is shown at the bottom of the screen.
+func bottomPlayerHeight() int {
+	switch {
+	case layout.player == nil || layout.playerTop:
+		return 0
+
+	case IsCompactPlayer():
+		return 1
+	}
+
+	return 2
+}
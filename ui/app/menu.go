@@ -134,25 +134,7 @@ func DrawMenu(x int, region string) {
 		switch keybinding.KeyOperation(event) {
 		case keybinding.KeySelect:
 			row, _ := modal.Table.GetSelection()
-			ref := modal.Table.GetCell(row, 0).GetReference()
-
-			if op, ok := ref.(*keybinding.KeyData); ok {
-				MenuKeybindings(event)
-
-				if op.Kb.Key != tcell.KeyRune {
-					op.Kb.Rune = rune(op.Kb.Key)
-				}
-
-				ev := tcell.NewEventKey(op.Kb.Key, op.Kb.Rune, op.Kb.Mod)
-
-				UI.Application.GetInputCapture()(ev)
-				if op.Global {
-					break
-				}
-				if menuArea.focus != nil {
-					menuArea.focus.InputHandler()(ev, nil)
-				}
-			}
+			selectMenuItem(modal, row)
 
 		case keybinding.KeyClose, keybinding.KeySwitch:
 			MenuKeybindings(event)
@@ -162,13 +144,23 @@ func DrawMenu(x int, region string) {
 	})
 
 	for row, item := range menuItems {
+		op := keybinding.OperationData(item)
+		if keybinding.Unbound(op) {
+			skipped++
+			continue
+		}
 		if visible, ok := menuArea.data.Visible[item]; ok && !visible(region) {
 			skipped++
 			continue
 		}
 
-		op := keybinding.OperationData(item)
-		keyname := keybinding.KeyName(op.Kb)
+		keyname := keybinding.BindingName(op)
+
+		itemRow := row - skipped
+		clicked := func() bool {
+			selectMenuItem(modal, itemRow)
+			return true
+		}
 
 		opwidth := len(op.Title) + len(keyname) + 10
 		if opwidth > width {
@@ -181,7 +173,8 @@ func DrawMenu(x int, region string) {
 			op.Title,
 		).
 			SetExpansion(1).
-			SetReference(op),
+			SetReference(op).
+			SetClickedFunc(clicked),
 		)
 
 		modal.Table.SetCell(row-skipped, 1, theme.NewTableCell(
@@ -190,7 +183,8 @@ func DrawMenu(x int, region string) {
 			keyname,
 		).
 			SetExpansion(1).
-			SetAlign(tview.AlignRight),
+			SetAlign(tview.AlignRight).
+			SetClickedFunc(clicked),
 		)
 	}
 
@@ -206,6 +200,32 @@ func DrawMenu(x int, region string) {
 	modal.Show(false)
 }
 
+// selectMenuItem closes the menu, and runs the operation at the provided row.
+func selectMenuItem(modal *Modal, row int) {
+	op, ok := modal.Table.GetCell(row, 0).GetReference().(*keybinding.KeyData)
+	if !ok {
+		return
+	}
+
+	MenuExit()
+	runOperation(op, menuArea.focus)
+}
+
+// runOperation runs the operation by sending its key event to the global
+// keybindings, and to the provided primitive if the operation is not global.
+func runOperation(op *keybinding.KeyData, focus tview.Primitive) {
+	ev := keybinding.OperationEvent(op)
+
+	UI.Application.GetInputCapture()(ev)
+	if op.Global || focus == nil {
+		return
+	}
+
+	focus.InputHandler()(ev, func(p tview.Primitive) {
+		UI.SetFocus(p)
+	})
+}
+
 // MenuHighlightHandler draws the menu based on which menu name is highlighted.
 func MenuHighlightHandler(added, removed, remaining []string) {
 	if added == nil {
@@ -203,12 +203,17 @@ func ResizeModal() {
 
 		switch {
 		case modal.attach:
+			playerHeight := bottomPlayerHeight()
+
 			switch {
-			case playerShown() && modal.y.GetItemCount() == 3:
-				modal.y.AddItem(nil, 2, 0, false)
+			case playerHeight > 0 && modal.y.GetItemCount() == 3:
+				modal.y.AddItem(nil, playerHeight, 0, false)
 
-			case !playerShown() && modal.y.GetItemCount() > 3:
+			case playerHeight == 0 && modal.y.GetItemCount() > 3:
 				modal.y.RemoveItemIndex(modal.y.GetItemCount() - 1)
+
+			case playerHeight > 0:
+				modal.y.ResizeItem(modal.y.GetItem(modal.y.GetItemCount()-1), playerHeight, 0)
 			}
 
 			modal.y.ResizeItem(modal.Flex, pageHeight, 0)
@@ -238,8 +243,3 @@ func ResizeModal() {
 		go UI.Draw()
 	}
 }
-
-// playerShown returns whether the player is shown or not.
-func playerShown() bool {
-	return UI.Layout.GetItemCount() > 5
-}
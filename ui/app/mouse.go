@@ -0,0 +1,133 @@
+package app
+
+import (
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/keybinding"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// setupMouse enables mouse support if it is enabled in the configuration.
+func setupMouse() {
+	if !cmd.IsOptionEnabled("mouse") {
+		return
+	}
+
+	UI.EnableMouse(true)
+	UI.SetMouseCapture(mouseHandler)
+
+	UI.Menu.SetMouseCapture(menuMouseHandler)
+	UI.Tabs.SetMouseCapture(tabsMouseHandler)
+}
+
+// MouseRegion returns the region of the textview at the provided screen position.
+func MouseRegion(textview *tview.TextView, x, y int) string {
+	var selected string
+
+	if !textview.InRect(x, y) {
+		return ""
+	}
+
+	rectX, _, _, _ := textview.GetInnerRect()
+
+	start := -1
+	for _, region := range textview.GetRegionIDs() {
+		if pos := textview.GetRegionStart(region); pos <= x-rectX && pos > start {
+			selected, start = region, pos
+		}
+	}
+
+	return selected
+}
+
+// mouseHandler handles the mouse events for the application.
+func mouseHandler(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+	x, y := event.Position()
+
+	switch action {
+	case tview.MouseLeftDown:
+		for index, pages := range layout.pages {
+			if index != layout.active && pages != nil && pages.InRect(x, y) {
+				FocusPane(index)
+			}
+		}
+
+		if menuArea.modal != nil && menuArea.modal.Open &&
+			!menuArea.modal.Flex.InRect(x, y) && !UI.Menu.InRect(x, y) {
+			MenuExit()
+		}
+
+	case tview.MouseLeftDoubleClick:
+		if table := FocusedTable(); table != nil && table.InRect(x, y) {
+			activateTable(table)
+			return nil, action
+		}
+	}
+
+	return event, action
+}
+
+// menuMouseHandler opens or closes the menu which was clicked on.
+func menuMouseHandler(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	x, y := event.Position()
+	if !UI.Menu.InRect(x, y) {
+		return action, event
+	}
+
+	if action == tview.MouseLeftClick {
+		region := MouseRegion(UI.Menu, x, y)
+		highlights := UI.Menu.GetHighlights()
+
+		MenuExit()
+		if region != "" && (highlights == nil || highlights[0] != region) {
+			UI.Menu.Highlight(region)
+		}
+	}
+
+	return action, nil
+}
+
+// tabsMouseHandler switches to the tab which was clicked on.
+func tabsMouseHandler(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	x, y := event.Position()
+	if !UI.Tabs.InRect(x, y) {
+		return action, event
+	}
+
+	if action == tview.MouseLeftClick {
+		focused := UI.GetFocus()
+		if _, ok := focused.(*tview.InputField); ok || focused == nil {
+			return action, nil
+		}
+
+		if clickedTab = MouseRegion(UI.Tabs, x, y); clickedTab != "" {
+			runOperation(keybinding.OperationData(keybinding.KeySwitch), focused)
+			clickedTab = ""
+		}
+	}
+
+	return action, nil
+}
+
+// activateTable runs the appropriate operation for the selected entry
+// of the table, which was double-clicked on.
+func activateTable(table *tview.Table) {
+	key := keybinding.KeySelect
+
+	if len(modals) == 0 || !modals[len(modals)-1].Flex.HasFocus() {
+		if info, err := FocusedTableReference(); err == nil {
+			switch info.Type {
+			case "video":
+				key = keybinding.KeyPlayerPlayAudio
+
+			case "playlist":
+				key = keybinding.KeyPlaylist
+
+			case "channel":
+				key = keybinding.KeyChannelVideos
+			}
+		}
+	}
+
+	runOperation(keybinding.OperationData(key), table)
+}
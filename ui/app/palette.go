@@ -0,0 +1,301 @@
+package app
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/keybinding"
+	"github.com/darkhz/invidtui/ui/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// CommandPalette stores the command palette popup and the commands
+// available within the context it was opened in.
+type CommandPalette struct {
+	modal    *Modal
+	focus    tview.Primitive
+	commands []PaletteCommand
+}
+
+// PaletteCommand describes a command within the command palette.
+type PaletteCommand struct {
+	Key  keybinding.Key
+	Data *keybinding.KeyData
+
+	title  string
+	recent int
+	score  int
+}
+
+// MaxRecentCommands is the maximum number of recently used commands that are stored.
+const MaxRecentCommands = 20
+
+var palette CommandPalette
+
+// ShowCommandPalette shows a prompt to search for and run the operations
+// available within the currently focused context.
+func ShowCommandPalette() {
+	if palette.modal != nil && palette.modal.Open {
+		return
+	}
+
+	palette.focus = menuArea.focus
+	palette.commands = paletteCommands(menuArea.context)
+
+	property := theme.ThemeProperty{
+		Context: theme.ThemeContextCommands,
+		Item:    theme.ThemePopupBackground,
+	}
+
+	palette.modal = NewModal("command_palette", "Commands", nil, 0, 0, property)
+
+	UI.Status.SetInput("Command:", 0, true, nil, paletteKeybindings, renderPalette)
+	renderPalette("")
+}
+
+// paletteKeybindings describes the keybindings for the command palette.
+func paletteKeybindings(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyUp, tcell.KeyDown:
+		palette.modal.Table.InputHandler()(event, nil)
+		return nil
+	}
+
+	switch keybinding.KeyOperation(event, keybinding.KeyContextCommon) {
+	case keybinding.KeySelect:
+		var command *PaletteCommand
+
+		row, _ := palette.modal.Table.GetSelection()
+		if ref, ok := palette.modal.Table.GetCell(row, 0).GetReference().(*PaletteCommand); ok {
+			command = ref
+		}
+
+		closePalette()
+
+		if command != nil {
+			runCommand(*command)
+		}
+
+		return nil
+
+	case keybinding.KeyClose:
+		closePalette()
+		return nil
+	}
+
+	return event
+}
+
+// renderPalette renders the commands which match the provided text.
+func renderPalette(text string) {
+	var width int
+
+	table := palette.modal.Table
+	table.Clear()
+
+	commands := matchCommands(palette.commands, text)
+	for row, command := range commands {
+		command := command
+
+		keyname := keybinding.BindingName(command.Data)
+		if w := len(command.title) + len(keyname) + 10; w > width {
+			width = w
+		}
+
+		table.SetCell(row, 0, theme.NewTableCell(
+			theme.ThemeContextCommands,
+			theme.ThemeDescription,
+			tview.Escape(command.title),
+		).
+			SetExpansion(1).
+			SetReference(&command),
+		)
+
+		table.SetCell(row, 1, theme.NewTableCell(
+			theme.ThemeContextCommands,
+			theme.ThemeName,
+			string(command.Data.Context),
+		).
+			SetSelectable(true).
+			SetAlign(tview.AlignRight),
+		)
+
+		table.SetCell(row, 2, theme.NewTableCell(
+			theme.ThemeContextCommands,
+			theme.ThemeKeybinding,
+			keyname,
+		).
+			SetSelectable(true).
+			SetAlign(tview.AlignRight),
+		)
+	}
+
+	if palette.modal.Open {
+		palette.modal.Exit(true)
+	}
+	if len(commands) == 0 {
+		return
+	}
+
+	table.Select(0, 0)
+
+	palette.modal.Width = width + 20
+	palette.modal.Height = len(commands) + 3
+	if palette.modal.Height > 15 {
+		palette.modal.Height = 15
+	}
+
+	palette.modal.Show(true)
+	UI.SetFocus(UI.Status.InputField)
+}
+
+// closePalette closes the command palette.
+func closePalette() {
+	UI.Status.SwitchToPage("messages")
+	palette.modal.Exit(false)
+
+	if palette.focus != nil {
+		UI.SetFocus(palette.focus)
+	}
+}
+
+// runCommand runs the command, and marks it as recently used.
+func runCommand(command PaletteCommand) {
+	addRecentCommand(command.Key)
+	runOperation(command.Data, palette.focus)
+}
+
+// paletteCommands returns the commands available within the provided context.
+func paletteCommands(context keybinding.KeyContext) []PaletteCommand {
+	var commands []PaletteCommand
+
+	added := make(map[keybinding.Key]struct{})
+	recent := make(map[keybinding.Key]int)
+	for i, key := range cmd.Settings.RecentCommands {
+		recent[keybinding.Key(key)] = i + 1
+	}
+
+	add := func(key keybinding.Key, menuType string) {
+		data := keybinding.OperationData(key)
+		if _, ok := added[key]; ok || data == nil || keybinding.Unbound(data) || key == keybinding.KeyCommandPalette {
+			return
+		}
+		if visible, ok := menuArea.data.Visible[key]; ok && !visible(menuType) {
+			return
+		}
+
+		title := data.Title
+		if title == "" {
+			title = string(key)
+		}
+
+		added[key] = struct{}{}
+		commands = append(commands, PaletteCommand{
+			Key:  key,
+			Data: data,
+
+			title:  title,
+			recent: recent[key],
+		})
+	}
+
+	for _, key := range menuArea.data.Items[context] {
+		add(key, string(context))
+	}
+
+	for key, data := range keybinding.OperationKeys {
+		if data.Context == context || data.Context == keybinding.KeyContextCommon || data.Global {
+			add(key, string(data.Context))
+		}
+	}
+
+	return commands
+}
+
+// matchCommands returns the commands which fuzzy match the provided text.
+// Recently used commands are listed first, followed by the best matches.
+func matchCommands(commands []PaletteCommand, text string) []PaletteCommand {
+	var matches []PaletteCommand
+
+	for _, command := range commands {
+		score, ok := fuzzyScore(command.title+" "+string(command.Data.Context), text)
+		if !ok {
+			continue
+		}
+
+		command.score = score
+		matches = append(matches, command)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+
+		switch {
+		case a.recent != b.recent:
+			return a.recent != 0 && (b.recent == 0 || a.recent < b.recent)
+
+		case a.score != b.score:
+			return a.score > b.score
+		}
+
+		return a.title < b.title
+	})
+
+	return matches
+}
+
+// fuzzyScore returns whether all characters of the pattern appear in order
+// within the text, and a score which favours consecutive and word-initial matches.
+func fuzzyScore(text, pattern string) (int, bool) {
+	var score, index int
+
+	textRunes := []rune(strings.ToLower(text))
+	previous := -2
+
+	for _, p := range strings.ToLower(pattern) {
+		if unicode.IsSpace(p) {
+			continue
+		}
+
+		found := false
+		for ; index < len(textRunes); index++ {
+			if textRunes[index] != p {
+				continue
+			}
+
+			score++
+			if index == previous+1 {
+				score += 3
+			}
+			if index == 0 || unicode.IsSpace(textRunes[index-1]) {
+				score += 2
+			}
+
+			previous = index
+			index++
+			found = true
+
+			break
+		}
+		if !found {
+			return 0, false
+		}
+	}
+
+	return score, true
+}
+
+// addRecentCommand marks the command as the most recently used.
+func addRecentCommand(key keybinding.Key) {
+	recent := []string{string(key)}
+	for _, k := range cmd.Settings.RecentCommands {
+		if k != string(key) && len(recent) < MaxRecentCommands {
+			recent = append(recent, k)
+		}
+	}
+
+	cmd.Settings.RecentCommands = recent
+}
@@ -20,7 +20,13 @@ type TabInfo struct {
 	ID, Title string
 }
 
-var currentTab = &Tab{}
+var (
+	currentTab = &Tab{}
+
+	// clickedTab stores the tab which was clicked with the mouse,
+	// and is selected on the next tab switch.
+	clickedTab string
+)
 
 // SetTab sets the tab.
 func SetTab(tabInfo Tab, context theme.ThemeContext) {
@@ -116,6 +122,15 @@ Selected:
 		}
 	}
 
+	if textview == UI.Tabs && clickedTab != "" {
+		for i, region := range regions {
+			if region == clickedTab {
+				currentView = i
+				goto Highlight
+			}
+		}
+	}
+
 	if reverse {
 		currentView--
 	} else {
@@ -128,6 +143,7 @@ Selected:
 		currentView = len(regions) - 1
 	}
 
+Highlight:
 	textview.Highlight(regions[currentView])
 	textview.ScrollToHighlight()
 
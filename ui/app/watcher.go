@@ -0,0 +1,118 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/theme"
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay is the time to wait for further changes to the
+// configuration files before reloading them.
+const reloadDelay = 300 * time.Millisecond
+
+// WatchConfig watches the configuration and theme files,
+// and applies any changes made to them.
+func WatchConfig() {
+	var reloadConfig, reloadTheme bool
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		ShowError(fmt.Errorf("Config: Cannot watch configuration files: %w", err))
+		return
+	}
+	defer watcher.Close()
+
+	configFile, err := cmd.GetPath("invidtui.conf")
+	if err != nil {
+		ShowError(err)
+		return
+	}
+
+	themeDir, err := cmd.GetConfigDir("themes")
+	if err != nil {
+		ShowError(err)
+		return
+	}
+
+	for _, dir := range []string{filepath.Dir(configFile), themeDir} {
+		if err := watcher.Add(dir); err != nil {
+			ShowError(fmt.Errorf("Config: Cannot watch %s: %w", dir, err))
+			return
+		}
+	}
+
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+
+	for {
+		select {
+		case <-UI.Closed.Done():
+			return
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+
+			switch filepath.Clean(event.Name) {
+			case configFile:
+				reloadConfig = true
+
+			case theme.CurrentFile():
+				reloadTheme = true
+
+			default:
+				continue
+			}
+
+			timer.Reset(reloadDelay)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			ShowError(fmt.Errorf("Config: Cannot watch configuration files: %w", err))
+
+		case <-timer.C:
+			reloadFiles(reloadConfig, reloadTheme)
+			reloadConfig, reloadTheme = false, false
+		}
+	}
+}
+
+// reloadFiles reloads the theme and keybindings from the configuration file,
+// and the currently applied theme file.
+func reloadFiles(reloadConfig, reloadTheme bool) {
+	var err error
+
+	UI.QueueUpdate(func() {
+		if reloadConfig {
+			err = cmd.ReloadConfig(cmd.ConfigTheme, cmd.ConfigKeybindings)
+		}
+		if err == nil && reloadTheme {
+			if themeFile := theme.CurrentFile(); themeFile != "" {
+				err = theme.ParseFile(themeFile)
+			}
+		}
+
+		if err == nil {
+			theme.UpdateThemeVersion()
+		}
+	})
+
+	if err != nil {
+		ShowError(err)
+		return
+	}
+
+	ShowInfo("Configuration reloaded", false)
+	go UI.Draw()
+}
@@ -14,7 +14,17 @@ import (
 // KbConfig describes the keybinding configuration handler.
 type KbConfig struct{}
 
-var config KbConfig
+// savedBinding stores the keys bound to an operation.
+type savedBinding struct {
+	kb       Keybinding
+	sequence []Keybinding
+}
+
+var (
+	config KbConfig
+
+	defaultBindings = saveBindings()
+)
 
 // GetConfigHandler returns the keybinding configuration handler.
 func GetConfigHandler() *KbConfig {
@@ -22,7 +32,22 @@ func GetConfigHandler() *KbConfig {
 }
 
 // Parse parses the keybindings from the configuration.
+// The keybindings are reset to their defaults before parsing, and
+// if parsing fails, the previous keybindings are restored.
 func (c *KbConfig) Parse(k *koanf.Koanf, dir string) error {
+	previous := saveBindings()
+	restoreBindings(defaultBindings)
+
+	if err := parseBindings(k); err != nil {
+		restoreBindings(previous)
+		return err
+	}
+
+	return nil
+}
+
+// parseBindings parses and validates the keybindings from the configuration.
+func parseBindings(k *koanf.Koanf) error {
 	if !k.Exists("keybindings") {
 		return nil
 	}
@@ -43,25 +68,17 @@ func (c *KbConfig) Parse(k *koanf.Koanf, dir string) error {
 		}
 	}
 
-	keyMap := make(map[Keybinding]struct{})
+	keyMap := make(map[string]struct{})
 	keyErrors := strings.Builder{}
 
 	fmt.Fprintf(&keyErrors, "Config: The following keybindings will conflict:\n")
 
 	for keyType, keydata := range OperationKeys {
-		for existing, data := range OperationKeys {
-			if data.Kb == keydata.Kb && data.Title != keydata.Title {
-				if data.Context == keydata.Context || data.Global || keydata.Global {
-					goto KeyError
-				}
-
-				continue
-
-			KeyError:
-				if _, ok := keyMap[keydata.Kb]; !ok {
-					keyMap[keydata.Kb] = struct{}{}
-					fmt.Fprintf(&keyErrors, "- %s will override %s (%s)\n", keyType, existing, KeyName(keydata.Kb))
-				}
+		for _, existing := range Conflicts(keyType) {
+			id := sequenceID(bindingKeys(keydata))
+			if _, ok := keyMap[id]; !ok {
+				keyMap[id] = struct{}{}
+				fmt.Fprintf(&keyErrors, "- %s will override %s (%s)\n", keyType, existing, BindingName(keydata))
 			}
 		}
 	}
@@ -83,24 +100,164 @@ func (c *KbConfig) Generate(k *koanf.Koanf) (interface{}, error) {
 	return kbMap, nil
 }
 
-// checkBindings validates the provided keybinding.
-//
-//gocyclo:ignore
+// saveBindings returns the keys which are currently bound to each operation.
+func saveBindings() map[Key]savedBinding {
+	bindings := make(map[Key]savedBinding, len(OperationKeys))
+	for key, data := range OperationKeys {
+		bindings[key] = savedBinding{data.Kb, data.Sequence}
+	}
+
+	return bindings
+}
+
+// restoreBindings binds the provided keys to each operation,
+// and clears the keybinding cache.
+func restoreBindings(bindings map[Key]savedBinding) {
+	for key, binding := range bindings {
+		if data, ok := OperationKeys[key]; ok {
+			data.Kb, data.Sequence = binding.kb, binding.sequence
+		}
+	}
+
+	Keys, SequenceKeys = nil, nil
+}
+
+// Conflicts returns the operations whose keybindings conflict with
+// the keybinding of the provided operation.
+func Conflicts(operation Key) []Key {
+	var conflicts []Key
+
+	keydata, ok := OperationKeys[operation]
+	if !ok || Unbound(keydata) {
+		return nil
+	}
+
+	for existing, data := range OperationKeys {
+		if data.Title == keydata.Title || Unbound(data) {
+			continue
+		}
+
+		if data.Context != keydata.Context && !data.Global && !keydata.Global {
+			continue
+		}
+
+		if same, prefix := compareBindings(data, keydata); same || prefix {
+			conflicts = append(conflicts, existing)
+		}
+	}
+
+	return conflicts
+}
+
+// Unbound returns whether no keys are bound to the operation.
+func Unbound(data *KeyData) bool {
+	return data.Sequence == nil && data.Kb == Keybinding{}
+}
+
+// checkBindings validates the provided keybinding or key sequence.
 func checkBindings(keyType, key string, keyNames map[string]tcell.Key) error {
-	var runes []rune
-	var keys []tcell.Key
+	var keys []Keybinding
 
-	if _, ok := OperationKeys[Key(keyType)]; !ok {
+	data, ok := OperationKeys[Key(keyType)]
+	if !ok {
 		return fmt.Errorf("Config: Invalid key type %s", keyType)
 	}
 
+	if strings.EqualFold(strings.TrimSpace(key), "none") {
+		data.Kb, data.Sequence = Keybinding{}, nil
+		return nil
+	}
+
+	for _, step := range sequenceSteps(key) {
+		keybinding, err := parseBinding(keyType, key, step, keyNames)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, keybinding)
+	}
+	if keys == nil {
+		return fmt.Errorf("Config: No key specified or invalid keybinding for %s (%s)", keyType, key)
+	}
+
+	data.Kb = keys[len(keys)-1]
+	data.Sequence = nil
+	if len(keys) > 1 {
+		data.Sequence = keys
+	}
+
+	return nil
+}
+
+// sequenceSteps splits the key sequence into its keys. Modifiers which are
+// separated from their key by a space are joined with the key.
+func sequenceSteps(key string) []string {
+	var steps []string
+	var modifiers string
+
+	for _, step := range strings.Fields(key) {
+		isModifier := true
+		for _, token := range strings.Split(step, "+") {
+			switch strings.ToLower(token) {
+			case "ctrl", "alt", "shift", "":
+				continue
+			}
+
+			isModifier = false
+		}
+
+		if isModifier {
+			modifiers += strings.TrimSuffix(step, "+") + "+"
+			continue
+		}
+
+		steps = append(steps, modifiers+step)
+		modifiers = ""
+	}
+
+	return steps
+}
+
+// compareBindings returns whether the keybindings are identical, and whether
+// one of them is the beginning of the other's key sequence.
+func compareBindings(a, b *KeyData) (bool, bool) {
+	x, y := bindingKeys(a), bindingKeys(b)
+	if len(x) > len(y) {
+		x, y = y, x
+	}
+
+	for i := range x {
+		if x[i] != y[i] {
+			return false, false
+		}
+	}
+
+	return len(x) == len(y), len(x) != len(y)
+}
+
+// bindingKeys returns the keys bound to the operation.
+func bindingKeys(data *KeyData) []Keybinding {
+	if data.Sequence != nil {
+		return data.Sequence
+	}
+
+	return []Keybinding{data.Kb}
+}
+
+// parseBinding parses a single key within the provided keybinding.
+//
+//gocyclo:ignore
+func parseBinding(keyType, key, step string, keyNames map[string]tcell.Key) (Keybinding, error) {
+	var runes []rune
+	var keys []tcell.Key
+
 	keybinding := Keybinding{
 		Key:  tcell.KeyRune,
 		Rune: ' ',
 		Mod:  tcell.ModNone,
 	}
 
-	tokens := strings.FieldsFunc(key, func(c rune) bool {
+	tokens := strings.FieldsFunc(step, func(c rune) bool {
 		return unicode.IsSpace(c) || c == '+'
 	})
 
@@ -146,7 +303,7 @@ func checkBindings(keyType, key string, keyNames map[string]tcell.Key) error {
 	}
 
 	if keys != nil && runes != nil || len(runes) > 1 || len(keys) > 1 {
-		return fmt.Errorf("Config: More than one key entered for %s (%s)", keyType, key)
+		return keybinding, fmt.Errorf("Config: More than one key entered for %s (%s)", keyType, key)
 	}
 
 	if keybinding.Mod&tcell.ModShift != 0 {
@@ -185,10 +342,8 @@ func checkBindings(keyType, key string, keyNames map[string]tcell.Key) error {
 	}
 
 	if keys == nil && runes == nil {
-		return fmt.Errorf("Config: No key specified or invalid keybinding for %s (%s)", keyType, key)
+		return keybinding, fmt.Errorf("Config: No key specified or invalid keybinding for %s (%s)", keyType, key)
 	}
 
-	OperationKeys[Key(keyType)].Kb = keybinding
-
-	return nil
+	return keybinding, nil
 }
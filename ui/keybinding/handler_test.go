@@ -0,0 +1,62 @@
+package keybinding
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestSequenceSteps(t *testing.T) {
+	tests := []struct {
+		key   string
+		steps []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"Ctrl+a", []string{"Ctrl+a"}},
+		{"g g", []string{"g", "g"}},
+		{"  g   t  ", []string{"g", "t"}},
+		{"Ctrl+x Ctrl+s", []string{"Ctrl+x", "Ctrl+s"}},
+		{"Ctrl+ x", []string{"Ctrl+x"}},
+		{"Ctrl Alt+ x d", []string{"Ctrl+Alt+x", "d"}},
+		{"Shift+ Enter", []string{"Shift+Enter"}},
+		{"Ctrl+", nil},
+	}
+
+	for _, test := range tests {
+		if steps := sequenceSteps(test.key); !reflect.DeepEqual(steps, test.steps) {
+			t.Errorf("sequenceSteps(%q) = %q, want %q", test.key, steps, test.steps)
+		}
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	for operation := range OperationKeys {
+		if conflicts := Conflicts(operation); conflicts != nil {
+			t.Errorf("Conflicts(%s) = %v, want no conflicts for the default keybindings", operation, conflicts)
+		}
+	}
+
+	tests := []struct {
+		operation Key
+		kb        Keybinding
+		conflicts []Key
+	}{
+		{KeyThemeEditorBackground, Keybinding{tcell.KeyRune, 'b', tcell.ModNone}, []Key{KeyAudioURL}},
+		{KeyThemeEditorForeground, Keybinding{tcell.KeyRune, 'f', tcell.ModNone}, []Key{KeyFetcher}},
+		{KeyThemeEditorClear, Keybinding{tcell.KeyRune, 'q', tcell.ModNone}, []Key{KeyQueue}},
+		{KeyThemeEditorClear, Keybinding{tcell.KeyRune, 'd', tcell.ModNone}, nil},
+	}
+
+	for _, test := range tests {
+		data := OperationKeys[test.operation]
+		kb := data.Kb
+
+		data.Kb = test.kb
+		if conflicts := Conflicts(test.operation); !reflect.DeepEqual(conflicts, test.conflicts) {
+			t.Errorf("Conflicts(%s) with %s = %v, want %v", test.operation, BindingName(data), conflicts, test.conflicts)
+		}
+		data.Kb = kb
+	}
+}
@@ -6,10 +6,11 @@ import (
 
 // KeyData stores the metadata for the key.
 type KeyData struct {
-	Title   string
-	Context KeyContext
-	Kb      Keybinding
-	Global  bool
+	Title    string
+	Context  KeyContext
+	Kb       Keybinding
+	Sequence []Keybinding
+	Global   bool
 }
 
 // Keybinding stores the keybinding.
@@ -29,6 +30,18 @@ const (
 	KeySuspend                 Key = "Suspend"
 	KeyInstancesList           Key = "InstancesList"
 	KeyTheme                   Key = "Theme"
+	KeyThemeEditor             Key = "ThemeEditor"
+	KeyViewBack                Key = "ViewBack"
+	KeyViewForward             Key = "ViewForward"
+	KeyViewHistory             Key = "ViewHistory"
+	KeyCommandPalette          Key = "CommandPalette"
+	KeyHelp                    Key = "Help"
+	KeyLayoutCompactPlayer     Key = "LayoutCompactPlayer"
+	KeyLayoutPlayerPosition    Key = "LayoutPlayerPosition"
+	KeyLayoutPaneIncrease      Key = "LayoutPaneIncrease"
+	KeyLayoutPaneDecrease      Key = "LayoutPaneDecrease"
+	KeySplitView               Key = "SplitView"
+	KeySplitSwitch             Key = "SplitSwitch"
 	KeyQuit                    Key = "Quit"
 	KeySearchStart             Key = "SearchStart"
 	KeySearchSuggestions       Key = "SearchSuggestions"
@@ -51,6 +64,7 @@ const (
 	KeyDownloadView            Key = "DownloadView"
 	KeyDownloadOptions         Key = "DownloadOptions"
 	KeyDownloadCancel          Key = "DownloadCancel"
+	KeyDownloadLimit           Key = "DownloadLimit"
 	KeyQueue                   Key = "Queue"
 	KeyQueuePlayMove           Key = "QueuePlayMove"
 	KeyQueueSave               Key = "QueueSave"
@@ -86,8 +100,14 @@ const (
 	KeyPlayerVolumeDecrease    Key = "PlayerVolumeDecrease"
 	KeyPlayerInfoScrollUp      Key = "PlayerInfoScrollUp"
 	KeyPlayerInfoScrollDown    Key = "PlayerInfoScrollDown"
+	KeyPlayerCast              Key = "PlayerCast"
 	KeyComments                Key = "Comments"
 	KeyCommentReplies          Key = "CommentReplies"
+	KeyThemeEditorForeground   Key = "ThemeEditorForeground"
+	KeyThemeEditorBackground   Key = "ThemeEditorBackground"
+	KeyThemeEditorAttributes   Key = "ThemeEditorAttributes"
+	KeyThemeEditorClear        Key = "ThemeEditorClear"
+	KeyThemeEditorSave         Key = "ThemeEditorSave"
 	KeySwitch                  Key = "Switch"
 	KeyPlaylist                Key = "Playlist"
 	KeyPlaylistSave            Key = "PlaylistSave"
@@ -111,21 +131,22 @@ type KeyContext string
 
 // The different context types for keybindings.
 const (
-	KeyContextApp       KeyContext = "App"
-	KeyContextPlayer    KeyContext = "Player"
-	KeyContextCommon    KeyContext = "Common"
-	KeyContextSearch    KeyContext = "Search"
-	KeyContextDashboard KeyContext = "Dashboard"
-	KeyContextFiles     KeyContext = "Files"
-	KeyContextDownloads KeyContext = "Downloads"
-	KeyContextQueue     KeyContext = "Queue"
-	KeyContextFetcher   KeyContext = "Fetcher"
-	KeyContextSeek      KeyContext = "Seek"
-	KeyContextComments  KeyContext = "Comments"
-	KeyContextStart     KeyContext = "Start"
-	KeyContextPlaylist  KeyContext = "Playlist"
-	KeyContextChannel   KeyContext = "Channel"
-	KeyContextHistory   KeyContext = "History"
+	KeyContextApp         KeyContext = "App"
+	KeyContextPlayer      KeyContext = "Player"
+	KeyContextCommon      KeyContext = "Common"
+	KeyContextSearch      KeyContext = "Search"
+	KeyContextDashboard   KeyContext = "Dashboard"
+	KeyContextFiles       KeyContext = "Files"
+	KeyContextDownloads   KeyContext = "Downloads"
+	KeyContextQueue       KeyContext = "Queue"
+	KeyContextFetcher     KeyContext = "Fetcher"
+	KeyContextSeek        KeyContext = "Seek"
+	KeyContextComments    KeyContext = "Comments"
+	KeyContextStart       KeyContext = "Start"
+	KeyContextPlaylist    KeyContext = "Playlist"
+	KeyContextChannel     KeyContext = "Channel"
+	KeyContextHistory     KeyContext = "History"
+	KeyContextThemeEditor KeyContext = "ThemeEditor"
 )
 
 var (
@@ -155,12 +176,84 @@ var (
 			Kb:      Keybinding{tcell.KeyRune, 'T', tcell.ModNone},
 			Global:  true,
 		},
+		KeyThemeEditor: {
+			Title:   "Edit Theme",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRune, 't', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyInstancesList: {
 			Title:   "List Instances",
 			Context: KeyContextApp,
 			Kb:      Keybinding{tcell.KeyRune, 'o', tcell.ModNone},
 			Global:  true,
 		},
+		KeyViewBack: {
+			Title:   "Previous View",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyLeft, ' ', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyViewForward: {
+			Title:   "Next View",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRight, ' ', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyViewHistory: {
+			Title:   "Navigation History",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRune, 'n', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyCommandPalette: {
+			Title:   "Command Palette",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyCtrlP, ' ', tcell.ModCtrl},
+			Global:  true,
+		},
+		KeyHelp: {
+			Title:   "Keybindings Help",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRune, '?', tcell.ModNone},
+			Global:  true,
+		},
+		KeyLayoutCompactPlayer: {
+			Title:   "Toggle Compact Player",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRune, 'p', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyLayoutPlayerPosition: {
+			Title:   "Move Player",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRune, 'P', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyLayoutPaneIncrease: {
+			Title:   "Increase Side Pane Width",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRune, '=', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyLayoutPaneDecrease: {
+			Title:   "Decrease Side Pane Width",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRune, '-', tcell.ModAlt},
+			Global:  true,
+		},
+		KeySplitView: {
+			Title:   "Toggle Split View",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRune, 'v', tcell.ModAlt},
+			Global:  true,
+		},
+		KeySplitSwitch: {
+			Title:   "Switch Split Pane",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRune, 'w', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyQuit: {
 			Title:   "Quit",
 			Context: KeyContextApp,
@@ -268,10 +361,16 @@ var (
 			Context: KeyContextDownloads,
 			Kb:      Keybinding{tcell.KeyRune, 'x', tcell.ModNone},
 		},
+		KeyDownloadLimit: {
+			Title:   "Limit Download Rate",
+			Context: KeyContextDownloads,
+			Kb:      Keybinding{tcell.KeyRune, 'L', tcell.ModNone},
+		},
 		KeyQueue: {
 			Title:   "Show Queue",
 			Context: KeyContextQueue,
 			Kb:      Keybinding{tcell.KeyRune, 'q', tcell.ModNone},
+			Global:  true,
 		},
 		KeyQueuePlayMove: {
 			Title:   "Play/Replace",
@@ -307,6 +406,7 @@ var (
 			Title:   "Show Media Fetcher",
 			Context: KeyContextFetcher,
 			Kb:      Keybinding{tcell.KeyRune, 'f', tcell.ModNone},
+			Global:  true,
 		},
 		KeyFetcherReload: {
 			Title:   "Reload",
@@ -452,15 +552,23 @@ var (
 			Kb:      Keybinding{tcell.KeyDown, ' ', tcell.ModCtrl | tcell.ModAlt},
 			Global:  true,
 		},
+		KeyPlayerCast: {
+			Title:   "Cast to Device",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'c', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyAudioURL: {
 			Title:   "Play audio from URL",
 			Context: KeyContextPlayer,
 			Kb:      Keybinding{tcell.KeyRune, 'b', tcell.ModNone},
+			Global:  true,
 		},
 		KeyVideoURL: {
 			Title:   "Play video from URL",
 			Context: KeyContextPlayer,
 			Kb:      Keybinding{tcell.KeyRune, 'B', tcell.ModNone},
+			Global:  true,
 		},
 		KeyPlaylistSave: {
 			Title:   "Save Playlist",
@@ -477,6 +585,31 @@ var (
 			Context: KeyContextComments,
 			Kb:      Keybinding{tcell.KeyEnter, ' ', tcell.ModNone},
 		},
+		KeyThemeEditorForeground: {
+			Title:   "Set Foreground",
+			Context: KeyContextThemeEditor,
+			Kb:      Keybinding{tcell.KeyRune, 'f', tcell.ModAlt},
+		},
+		KeyThemeEditorBackground: {
+			Title:   "Set Background",
+			Context: KeyContextThemeEditor,
+			Kb:      Keybinding{tcell.KeyRune, 'b', tcell.ModAlt},
+		},
+		KeyThemeEditorAttributes: {
+			Title:   "Set Attributes",
+			Context: KeyContextThemeEditor,
+			Kb:      Keybinding{tcell.KeyRune, 't', tcell.ModNone},
+		},
+		KeyThemeEditorClear: {
+			Title:   "Clear Item",
+			Context: KeyContextThemeEditor,
+			Kb:      Keybinding{tcell.KeyRune, 'd', tcell.ModAlt},
+		},
+		KeyThemeEditorSave: {
+			Title:   "Save Theme",
+			Context: KeyContextThemeEditor,
+			Kb:      Keybinding{tcell.KeyCtrlS, ' ', tcell.ModCtrl},
+		},
 		KeySwitch: {
 			Title:   "Switch Tab/Input",
 			Context: KeyContextCommon,
@@ -565,23 +698,10 @@ func OperationData(operation Key) *KeyData {
 // KeyOperation returns the operation name for the provided keyID
 // and the keyboard event.
 func KeyOperation(event *tcell.EventKey, keyContexts ...KeyContext) Key {
-	if Keys == nil {
-		Keys = make(map[KeyContext]map[Keybinding]Key)
-		for keyName, key := range OperationKeys {
-			if Keys[key.Context] == nil {
-				Keys[key.Context] = make(map[Keybinding]Key)
-			}
+	buildKeys()
 
-			Keys[key.Context][key.Kb] = keyName
-		}
-	}
-
-	ch := event.Rune()
-	if event.Key() != tcell.KeyRune {
-		ch = ' '
-	}
-
-	kb := Keybinding{event.Key(), ch, event.Modifiers()}
+	kb := eventKeybinding(event)
+	id, isSequence := sequenceOperation(event)
 
 	for _, contexts := range [][]KeyContext{
 		keyContexts,
@@ -592,6 +712,14 @@ func KeyOperation(event *tcell.EventKey, keyContexts ...KeyContext) Key {
 		},
 	} {
 		for _, context := range contexts {
+			if isSequence {
+				if operation, ok := SequenceKeys[context][id]; ok {
+					return operation
+				}
+
+				continue
+			}
+
 			if operation, ok := Keys[context][kb]; ok {
 				return operation
 			}
@@ -601,6 +729,38 @@ func KeyOperation(event *tcell.EventKey, keyContexts ...KeyContext) Key {
 	return ""
 }
 
+// buildKeys matches the keybindings and the key sequences with their key types.
+func buildKeys() {
+	if Keys != nil {
+		return
+	}
+
+	Keys = make(map[KeyContext]map[Keybinding]Key)
+	SequenceKeys = make(map[KeyContext]map[string]Key)
+
+	for keyName, key := range OperationKeys {
+		if Unbound(key) {
+			continue
+		}
+
+		if key.Sequence != nil {
+			if SequenceKeys[key.Context] == nil {
+				SequenceKeys[key.Context] = make(map[string]Key)
+			}
+
+			SequenceKeys[key.Context][sequenceID(key.Sequence)] = keyName
+
+			continue
+		}
+
+		if Keys[key.Context] == nil {
+			Keys[key.Context] = make(map[Keybinding]Key)
+		}
+
+		Keys[key.Context][key.Kb] = keyName
+	}
+}
+
 // KeyName formats and returns the key's name.
 func KeyName(kb Keybinding) string {
 	if kb.Key == tcell.KeyRune {
@@ -621,6 +781,11 @@ func KeyName(kb Keybinding) string {
 
 // KeyEvent returns an event for the provided key.
 func KeyEvent(k Key) *tcell.EventKey {
-	kb := OperationData(k).Kb
-	return tcell.NewEventKey(kb.Key, kb.Rune, kb.Mod)
+	data := OperationData(k)
+	kb := data.Kb
+
+	event := tcell.NewEventKey(kb.Key, kb.Rune, kb.Mod)
+	resolveSequence(event, data)
+
+	return event
 }
@@ -0,0 +1,225 @@
+package keybinding
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// KeySequence stores the state of a multi-key sequence,
+// and the count prefix entered before it.
+type KeySequence struct {
+	keys  []Keybinding
+	count string
+
+	resolved     *tcell.EventKey
+	resolvedKeys []Keybinding
+
+	mutex sync.Mutex
+}
+
+// MaxSequenceCount is the maximum count that can be entered before a key.
+const MaxSequenceCount = 100
+
+var (
+	sequence KeySequence
+
+	// SequenceKeys match the key sequence to the key type.
+	SequenceKeys map[KeyContext]map[string]Key
+)
+
+// ParseSequence parses the key event as part of a key sequence or a count prefix.
+// Only the key sequences within the provided context and the global contexts are matched.
+// If the event completes a key or a key sequence, it is returned along with
+// the entered count, otherwise nil is returned and the event should be discarded.
+func ParseSequence(event *tcell.EventKey, context KeyContext) (*tcell.EventKey, int) {
+	buildKeys()
+
+	sequence.mutex.Lock()
+	defer sequence.mutex.Unlock()
+
+	if event == sequence.resolved {
+		return event, 1
+	}
+
+	kb := eventKeybinding(event)
+
+	if kb.Key == tcell.KeyEscape && (sequence.keys != nil || sequence.count != "") {
+		sequence.keys, sequence.count = nil, ""
+		return nil, 0
+	}
+
+	if sequence.keys == nil && isCountKey(kb) {
+		sequence.count += string(kb.Rune)
+		return nil, 0
+	}
+
+	for {
+		keys := append(append([]Keybinding{}, sequence.keys...), kb)
+
+		match, prefix := matchSequence(keys, context)
+		if !match && prefix {
+			sequence.keys = keys
+			return nil, 0
+		}
+		if !match && sequence.keys != nil {
+			sequence.keys = nil
+			if isCountKey(kb) {
+				sequence.count += string(kb.Rune)
+				return nil, 0
+			}
+
+			continue
+		}
+
+		count, _ := strconv.Atoi(sequence.count)
+		if count < 1 {
+			count = 1
+		} else if count > MaxSequenceCount {
+			count = MaxSequenceCount
+		}
+
+		sequence.keys, sequence.count = nil, ""
+		sequence.resolved, sequence.resolvedKeys = event, keys
+
+		return event, count
+	}
+}
+
+// PendingSequence returns the count and the keys of the sequence
+// which are currently being entered.
+func PendingSequence() string {
+	sequence.mutex.Lock()
+	defer sequence.mutex.Unlock()
+
+	names := []string{}
+	if sequence.count != "" {
+		names = append(names, sequence.count)
+	}
+	for _, kb := range sequence.keys {
+		names = append(names, KeyName(kb))
+	}
+
+	return strings.Join(names, " ")
+}
+
+// OperationEvent returns a key event which runs the provided operation.
+func OperationEvent(data *KeyData) *tcell.EventKey {
+	kb := data.Kb
+	if kb.Key != tcell.KeyRune {
+		kb.Rune = rune(kb.Key)
+	}
+
+	event := tcell.NewEventKey(kb.Key, kb.Rune, kb.Mod)
+	resolveSequence(event, data)
+
+	return event
+}
+
+// BindingName returns the name of the key or the key sequence bound to the operation.
+func BindingName(data *KeyData) string {
+	if Unbound(data) {
+		return ""
+	}
+
+	if data.Sequence == nil {
+		return KeyName(data.Kb)
+	}
+
+	names := make([]string, len(data.Sequence))
+	for i, kb := range data.Sequence {
+		names[i] = KeyName(kb)
+	}
+
+	return strings.Join(names, " ")
+}
+
+// sequenceOperation returns the key sequence which was resolved
+// for the provided event, if any.
+func sequenceOperation(event *tcell.EventKey) (string, bool) {
+	sequence.mutex.Lock()
+	defer sequence.mutex.Unlock()
+
+	if event != sequence.resolved || len(sequence.resolvedKeys) < 2 {
+		return "", false
+	}
+
+	return sequenceID(sequence.resolvedKeys), true
+}
+
+// resolveSequence marks the event as the completion of the operation's key sequence.
+func resolveSequence(event *tcell.EventKey, data *KeyData) {
+	if data.Sequence == nil {
+		return
+	}
+
+	sequence.mutex.Lock()
+	defer sequence.mutex.Unlock()
+
+	sequence.resolved, sequence.resolvedKeys = event, data.Sequence
+}
+
+// matchSequence returns whether the keys match a key sequence within the
+// provided context, or whether they are the beginning of such a key sequence.
+func matchSequence(keys []Keybinding, context KeyContext) (bool, bool) {
+	id := sequenceID(keys)
+	match, prefix := false, false
+
+	for _, seqContext := range []KeyContext{
+		context,
+		KeyContextApp,
+		KeyContextCommon,
+		KeyContextPlayer,
+	} {
+		for seqID := range SequenceKeys[seqContext] {
+			if seqID == id {
+				match = true
+			} else if strings.HasPrefix(seqID, id+" ") {
+				prefix = true
+			}
+		}
+	}
+
+	return match, prefix
+}
+
+// isCountKey returns whether the key can be used as part of a count prefix.
+func isCountKey(kb Keybinding) bool {
+	if kb.Key != tcell.KeyRune || kb.Mod != tcell.ModNone || !unicode.IsDigit(kb.Rune) {
+		return false
+	}
+	if kb.Rune == '0' && sequence.count == "" {
+		return false
+	}
+
+	for _, keys := range Keys {
+		if _, ok := keys[kb]; ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// eventKeybinding returns the keybinding for the event.
+func eventKeybinding(event *tcell.EventKey) Keybinding {
+	ch := event.Rune()
+	if event.Key() != tcell.KeyRune {
+		ch = ' '
+	}
+
+	return Keybinding{event.Key(), ch, event.Modifiers()}
+}
+
+// sequenceID returns an identifier for the key sequence.
+func sequenceID(keys []Keybinding) string {
+	ids := make([]string, len(keys))
+	for i, kb := range keys {
+		ids[i] = strconv.Itoa(int(kb.Key)) + ":" + strconv.Itoa(int(kb.Rune)) + ":" + strconv.Itoa(int(kb.Mod))
+	}
+
+	return strings.Join(ids, " ")
+}
@@ -162,3 +162,11 @@ func playlistRemoveFrom(menuType string) bool {
 
 	return isVideo(menuType) && prev.Name() == view.Dashboard.Name()
 }
+
+func sidePaneShown(menuType string) bool {
+	return app.SidePane() != nil
+}
+
+func isSplit(menuType string) bool {
+	return app.IsSplit()
+}
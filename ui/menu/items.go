@@ -16,6 +16,18 @@ var Items = &app.MenuData{
 			keybinding.KeyDownloadOptions,
 			keybinding.KeyInstancesList,
 			keybinding.KeyTheme,
+			keybinding.KeyThemeEditor,
+			keybinding.KeyViewBack,
+			keybinding.KeyViewForward,
+			keybinding.KeyViewHistory,
+			keybinding.KeyCommandPalette,
+			keybinding.KeyHelp,
+			keybinding.KeyLayoutCompactPlayer,
+			keybinding.KeyLayoutPlayerPosition,
+			keybinding.KeyLayoutPaneIncrease,
+			keybinding.KeyLayoutPaneDecrease,
+			keybinding.KeySplitView,
+			keybinding.KeySplitSwitch,
 			keybinding.KeyQuit,
 		},
 		keybinding.KeyContextStart: {
@@ -47,6 +59,7 @@ var Items = &app.MenuData{
 			keybinding.KeySelect,
 			keybinding.KeyDownloadChangeDir,
 			keybinding.KeyDownloadCancel,
+			keybinding.KeyDownloadLimit,
 			keybinding.KeyClose,
 		},
 		keybinding.KeyContextSearch: {
@@ -105,6 +118,7 @@ var Items = &app.MenuData{
 			keybinding.KeyAudioURL,
 			keybinding.KeyVideoURL,
 			keybinding.KeyPlayerSeekCustom,
+			keybinding.KeyPlayerCast,
 		},
 		keybinding.KeyContextQueue: {
 			keybinding.KeyQueuePlayMove,
@@ -128,6 +142,14 @@ var Items = &app.MenuData{
 			keybinding.KeyFetcherCancelAll,
 			keybinding.KeyFetcherClearCompleted,
 		},
+		keybinding.KeyContextThemeEditor: {
+			keybinding.KeyThemeEditorForeground,
+			keybinding.KeyThemeEditorBackground,
+			keybinding.KeyThemeEditorAttributes,
+			keybinding.KeyThemeEditorClear,
+			keybinding.KeyThemeEditorSave,
+			keybinding.KeyClose,
+		},
 		keybinding.KeyContextHistory: {
 			keybinding.KeyQuery,
 			keybinding.KeyChannelVideos,
@@ -149,6 +171,7 @@ var Items = &app.MenuData{
 		keybinding.KeyComments:                isVideo,
 		keybinding.KeyLink:                    isVideo,
 		keybinding.KeyDownloadCancel:          downloadViewVisible,
+		keybinding.KeyDownloadLimit:           downloadViewVisible,
 		keybinding.KeyAdd:                     add,
 		keybinding.KeyRemove:                  remove,
 		keybinding.KeyPlaylist:                isPlaylist,
@@ -177,5 +200,8 @@ var Items = &app.MenuData{
 		keybinding.KeyPlayerPlayAudio:         queuePlayMedia,
 		keybinding.KeyPlayerPlayVideo:         queuePlayMedia,
 		keybinding.KeyPlayerSeekCustom:        isPlaying,
+		keybinding.KeyLayoutPaneIncrease:      sidePaneShown,
+		keybinding.KeyLayoutPaneDecrease:      sidePaneShown,
+		keybinding.KeySplitSwitch:             isSplit,
 	},
 }
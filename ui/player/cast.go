@@ -0,0 +1,147 @@
+package player
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/ui/keybinding"
+	"github.com/darkhz/invidtui/ui/theme"
+	"github.com/gdamore/tcell/v2"
+)
+
+// showRenderers shows a popup with the local player and the UPnP media
+// renderers on the network, to select the device to play media on.
+func showRenderers() {
+	var renderersModal *app.Modal
+
+	app.ShowInfo("Searching for devices", true)
+
+	property := theme.ThemeProperty{
+		Context: theme.ThemeContextRenderers,
+		Item:    theme.ThemePopupBackground,
+	}
+
+	renderers, err := mp.DiscoverRenderers(context.Background(), 3*time.Second)
+	if err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	renderersView := theme.NewTable(property)
+	renderersView.SetSelectable(true, false)
+	renderersView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch keybinding.KeyOperation(event, keybinding.KeyContextCommon) {
+		case keybinding.KeySelect:
+			row, _ := renderersView.GetSelection()
+			renderer, _ := renderersView.GetCell(row, 0).GetReference().(*mp.Renderer)
+
+			renderersModal.Exit(false)
+			go castTo(renderer)
+
+		case keybinding.KeyClose:
+			renderersModal.Exit(false)
+		}
+
+		return event
+	})
+	renderersView.SetFocusFunc(func() {
+		app.SetContextMenu("", nil)
+	})
+
+	app.UI.QueueUpdateDraw(func() {
+		width := len("Local player")
+		current, casting := mp.CastRenderer()
+
+		devices := []*mp.Renderer{nil}
+		for i := range renderers {
+			devices = append(devices, &renderers[i])
+		}
+
+		for row, device := range devices {
+			name, selected := "Local player", !casting
+			if device != nil {
+				name = device.Name
+				selected = casting && device.Location == current.Location
+			}
+
+			if len(name) > width {
+				width = len(name)
+			}
+
+			tag := ""
+			if selected {
+				tag = "(Selected)"
+			}
+
+			renderersView.SetCell(row, 0, theme.NewTableCell(
+				theme.ThemeContextRenderers,
+				theme.ThemeName,
+				name,
+			).
+				SetReference(device),
+			)
+
+			renderersView.SetCell(row, 1, theme.NewTableCell(
+				theme.ThemeContextRenderers,
+				theme.ThemeTagChanged,
+				tag,
+			).
+				SetSelectable(true),
+			)
+		}
+
+		renderersModal = app.NewModal("renderers", "Play on device", renderersView, len(devices)+4, width+15, property)
+		renderersModal.Show(false)
+	})
+
+	app.ShowInfo("Found "+deviceCount(len(renderers)), false)
+}
+
+// castTo switches the playback to the renderer, or to the local player
+// if no renderer is provided. The current entry resumes playing on the
+// selected device from its current position.
+func castTo(renderer *mp.Renderer) {
+	current, casting := mp.CastRenderer()
+	if (renderer == nil && !casting) ||
+		(renderer != nil && casting && renderer.Location == current.Location) {
+		return
+	}
+
+	state := GetState()
+
+	if renderer == nil {
+		mp.Player().Stop()
+		mp.StopCasting()
+		app.ShowInfo("Playing on the local player", false)
+	} else {
+		mp.Cast(*renderer)
+		app.ShowInfo("Playing on "+renderer.Name, false)
+	}
+
+	mp.Player().SetLoopMode(player.queue.GetRepeatMode())
+
+	if state.Status == StatusStopped {
+		return
+	}
+
+	player.queue.storeMutex.Lock()
+	if data, ok := player.queue.GetEntryPointer(player.queue.Position()); ok {
+		position := state.Position
+		data.Timestamp = &position
+	}
+	player.queue.storeMutex.Unlock()
+
+	player.queue.Play(struct{}{})
+}
+
+// deviceCount returns the number of devices as text.
+func deviceCount(count int) string {
+	if count == 1 {
+		return "1 device"
+	}
+
+	return strconv.Itoa(count) + " devices"
+}
@@ -0,0 +1,420 @@
+package player
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/hooks"
+	inv "github.com/darkhz/invidtui/invidious"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/notify"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// QueueEntry describes a queue entry, as exposed to external controllers.
+type QueueEntry struct {
+	Position  int    `json:"position"`
+	VideoID   string `json:"videoId"`
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+	Duration  int64  `json:"duration"`
+	MediaType string `json:"mediaType"`
+	Thumbnail string `json:"thumbnail"`
+	Playing   bool   `json:"playing"`
+}
+
+// State describes the current state of the player.
+type State struct {
+	Status        string `json:"status"`
+	VideoID       string `json:"videoId"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	MediaType     string `json:"mediaType"`
+	Thumbnail     string `json:"thumbnail"`
+	Position      int64  `json:"position"`
+	Duration      int64  `json:"duration"`
+	Volume        int    `json:"volume"`
+	Muted         bool   `json:"muted"`
+	Repeat        string `json:"repeat"`
+	Shuffle       bool   `json:"shuffle"`
+	QueuePosition int    `json:"queuePosition"`
+	QueueCount    int    `json:"queueCount"`
+}
+
+// StateListener describes a function which is called with the previous
+// and the current state of the player, whenever the state changes.
+type StateListener func(previous, current State)
+
+// stateListeners stores the registered state listeners.
+type stateListeners struct {
+	list  []StateListener
+	mutex sync.Mutex
+}
+
+// The different playback statuses.
+const (
+	StatusPlaying = "playing"
+	StatusPaused  = "paused"
+	StatusStopped = "stopped"
+)
+
+var (
+	listeners stateListeners
+
+	// playback stores the state of the track which has started playing,
+	// to run the playback hooks.
+	playback struct {
+		state   State
+		started bool
+		mutex   sync.Mutex
+	}
+)
+
+// AddStateListener registers a listener for changes in the player state.
+func AddStateListener(listener StateListener) {
+	listeners.mutex.Lock()
+	defer listeners.mutex.Unlock()
+
+	listeners.list = append(listeners.list, listener)
+}
+
+// QueueEntries returns the entries within the queue.
+func QueueEntries() []QueueEntry {
+	var entries []QueueEntry
+
+	for i := 0; i < player.queue.Count(); i++ {
+		data, ok := player.queue.Get(i)
+		if !ok {
+			continue
+		}
+
+		entries = append(entries, queueEntry(i, data))
+	}
+
+	return entries
+}
+
+// QueueAdd adds the video or playlist from the provided URL or ID to the queue.
+func QueueAdd(uri string, audio bool) error {
+	id, mtype, _, err := utils.GetVPIDFromURL(uri)
+	if err != nil {
+		return err
+	}
+
+	info := inv.SearchData{
+		Title: uri,
+		Type:  mtype,
+	}
+	if mtype == "video" {
+		info.VideoID = id
+	} else {
+		info.PlaylistID = id
+	}
+
+	Play(audio, false, info)
+
+	return nil
+}
+
+// QueueMove moves the queue entry at the 'from' position to the 'to' position.
+func QueueMove(from, to int) error {
+	if err := checkQueuePosition(from); err != nil {
+		return err
+	}
+	if err := checkQueuePosition(to); err != nil {
+		return err
+	}
+
+	app.UI.QueueUpdateDraw(func() {
+		player.queue.Move(from, to)
+	})
+
+	return nil
+}
+
+// QueueDelete removes the queue entry at the provided position.
+func QueueDelete(position int) error {
+	if err := checkQueuePosition(position); err != nil {
+		return err
+	}
+
+	app.UI.QueueUpdateDraw(func() {
+		player.queue.removeEntry(position)
+	})
+
+	return nil
+}
+
+// QueuePlay plays the queue entry at the provided position.
+func QueuePlay(position int) error {
+	if err := checkQueuePosition(position); err != nil {
+		return err
+	}
+
+	player.setting.Store(true)
+	player.queue.SwitchToPosition(position)
+
+	return nil
+}
+
+// Control performs a playback operation. The 'seek' and 'volume' operations
+// require a value, which is the position in seconds and the volume respectively.
+func Control(operation string, value ...int64) error {
+	if (operation == "seek" || operation == "volume") && value == nil {
+		return fmt.Errorf("Player: No value provided for %s", operation)
+	}
+
+	switch operation {
+	case "play":
+		if mp.Player().Paused() {
+			mp.Player().TogglePaused()
+		}
+
+	case "pause":
+		if !mp.Player().Paused() {
+			mp.Player().TogglePaused()
+		}
+
+	case "toggle":
+		mp.Player().TogglePaused()
+
+	case "stop":
+		player.setting.Store(false)
+		go Hide()
+
+	case "next":
+		player.queue.Next(struct{}{})
+
+	case "previous":
+		player.queue.Previous(struct{}{})
+
+	case "seek":
+		mp.Player().SetPosition(value[0])
+
+	case "volume":
+		if err := mp.Player().Set("volume", strconv.FormatInt(value[0], 10)); err != nil {
+			return err
+		}
+
+	case "mute":
+		mp.Player().ToggleMuted()
+
+	case "repeat":
+		player.queue.ToggleRepeatMode()
+
+	case "shuffle":
+		player.queue.ToggleShuffle()
+
+	default:
+		return fmt.Errorf("Player: Invalid operation %s", operation)
+	}
+
+	sendPlayerEvents()
+
+	return nil
+}
+
+// GetState returns the current state of the player.
+func GetState() State {
+	state := State{
+		Status:        StatusStopped,
+		Volume:        mp.Player().Volume(),
+		Muted:         mp.Player().Muted(),
+		Shuffle:       player.queue.GetShuffleMode(),
+		QueuePosition: player.queue.Position(),
+		QueueCount:    player.queue.Count(),
+	}
+
+	switch player.queue.GetRepeatMode() {
+	case mp.RepeatModeFile:
+		state.Repeat = "file"
+
+	case mp.RepeatModePlaylist:
+		state.Repeat = "playlist"
+
+	default:
+		state.Repeat = "off"
+	}
+
+	data, ok := player.queue.GetCurrent()
+	if !ok || !player.status.Load() {
+		return state
+	}
+
+	switch {
+	case mp.Player().Finished():
+		state.Status = StatusStopped
+
+	case mp.Player().Paused():
+		state.Status = StatusPaused
+
+	default:
+		state.Status = StatusPlaying
+	}
+
+	state.VideoID = data.Reference.VideoID
+	state.Title = data.Reference.Title
+	state.Author = data.Reference.Author
+	state.MediaType = player.queue.GetMediaType()
+	state.Thumbnail = inv.VideoThumbnailURL(data.Reference)
+	state.Position = mp.Player().Position()
+	state.Duration = mp.Player().Duration()
+
+	return state
+}
+
+// Environ returns the state as a list of environment variables.
+func (s State) Environ() []string {
+	return []string{
+		"INVIDTUI_STATUS=" + s.Status,
+		"INVIDTUI_VIDEO_ID=" + s.VideoID,
+		"INVIDTUI_TITLE=" + s.Title,
+		"INVIDTUI_AUTHOR=" + s.Author,
+		"INVIDTUI_MEDIA_TYPE=" + s.MediaType,
+		"INVIDTUI_THUMBNAIL=" + s.Thumbnail,
+		"INVIDTUI_POSITION=" + strconv.FormatInt(s.Position, 10),
+		"INVIDTUI_DURATION=" + strconv.FormatInt(s.Duration, 10),
+		"INVIDTUI_QUEUE_POSITION=" + strconv.Itoa(s.QueuePosition),
+		"INVIDTUI_QUEUE_COUNT=" + strconv.Itoa(s.QueueCount),
+	}
+}
+
+// startTrack runs the hooks for a track which has started playing.
+// If the previous track did not reach its end, its end hook is run first.
+func startTrack() {
+	current := GetState()
+
+	playback.mutex.Lock()
+	previous, started := playback.state, playback.started
+	playback.state, playback.started = current, true
+	playback.mutex.Unlock()
+
+	if started {
+		previous.Status = StatusStopped
+		hooks.Run(hooks.EventEnd, previous.Environ())
+	}
+
+	hooks.Run(hooks.EventStart, current.Environ())
+}
+
+// endTrack runs the hooks for the currently playing track which has ended.
+// If finished is set, the track has played till its end, and the queue-empty
+// hook is run if there are no more entries to play.
+func endTrack(finished bool) {
+	current := GetState()
+
+	playback.mutex.Lock()
+	started := playback.started
+	if current.VideoID == "" {
+		current = playback.state
+	}
+	playback.started = false
+	playback.mutex.Unlock()
+
+	if !started {
+		return
+	}
+
+	current.Status = StatusStopped
+	hooks.Run(hooks.EventEnd, current.Environ())
+
+	if finished && !player.queue.hasNext() {
+		hooks.Run(hooks.EventQueueEmpty, current.Environ())
+	}
+}
+
+// pauseTrack runs the hooks for the currently playing track which has paused.
+func pauseTrack() {
+	playback.mutex.Lock()
+	started := playback.started
+	playback.mutex.Unlock()
+
+	if !started || mp.Player().Finished() {
+		return
+	}
+
+	hooks.Run(hooks.EventPause, GetState().Environ())
+}
+
+// notifyTrack sends a notification when a track starts playing.
+func notifyTrack(previous, current State) {
+	if current.Status == StatusStopped ||
+		(previous.Status != StatusStopped && previous.VideoID == current.VideoID) {
+		return
+	}
+
+	notify.Send(notify.Notification{
+		Category: notify.CategoryTrack,
+		Title:    current.Title,
+		Body:     current.Author,
+		Icon:     current.Thumbnail,
+	})
+}
+
+// watchState checks the player state every second, and
+// notifies the state listeners if the state has changed.
+func watchState() {
+	previous := State{Status: StatusStopped}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-app.UI.Closed.Done():
+			return
+
+		case <-ticker.C:
+		}
+
+		listeners.mutex.Lock()
+		list := listeners.list
+		listeners.mutex.Unlock()
+
+		if list == nil {
+			continue
+		}
+
+		current := GetState()
+		if current == previous {
+			continue
+		}
+
+		for _, listener := range list {
+			listener(previous, current)
+		}
+
+		previous = current
+	}
+}
+
+// queueEntry returns the external representation of the queue data.
+func queueEntry(position int, data QueueData) QueueEntry {
+	media := "Audio"
+	if !data.Audio {
+		media = "Video"
+	}
+
+	return QueueEntry{
+		Position:  position,
+		VideoID:   data.Reference.VideoID,
+		Title:     data.Reference.Title,
+		Author:    data.Reference.Author,
+		Duration:  data.Reference.LengthSeconds,
+		MediaType: media,
+		Thumbnail: inv.VideoThumbnailURL(data.Reference),
+		Playing:   data.Playing,
+	}
+}
+
+// checkQueuePosition checks whether the position is within the queue.
+func checkQueuePosition(position int) error {
+	if position < 0 || position >= player.queue.Count() {
+		return fmt.Errorf("Player: Invalid queue position %d", position)
+	}
+
+	return nil
+}
@@ -29,7 +29,7 @@ type Player struct {
 	seeker  CustomSeeker
 
 	infoID, thumbURI string
-	init             bool
+	init, compact    bool
 	width            int
 	history          History
 	states           []string
@@ -76,6 +76,7 @@ func setup() {
 		theme.NewTextView(player.property),
 		theme.NewTextView(player.property)
 	player.desc.SetTextAlign(tview.AlignCenter)
+	player.desc.SetMouseCapture(progressMouseHandler)
 	player.title.SetTextAlign(tview.AlignCenter)
 
 	player.image = theme.NewImage(
@@ -121,7 +122,10 @@ func Start() {
 
 	mp.SetEventHandler(mediaEventHandler)
 
+	AddStateListener(notifyTrack)
+
 	go playingStatusCheck()
+	go watchState()
 }
 
 // Stop stops the player.
@@ -132,6 +136,7 @@ func Stop() {
 	cmd.Settings.PlayerStates = player.states
 	player.mutex.Unlock()
 
+	mp.StopCasting()
 	mp.Player().Stop()
 	mp.Player().Exit()
 }
@@ -146,8 +151,13 @@ func Show() {
 	sendPlayingStatus(true)
 
 	app.UI.QueueUpdateDraw(func() {
-		app.UI.Layout.AddItem(player.flex, 2, 0, false)
-		app.ResizeModal()
+		app.ShowPlayer(player.flex)
+
+		if cmd.GetOptionValue("side-pane") == "info" {
+			ToggleInfo()
+		} else {
+			updateSidePane()
+		}
 	})
 }
 
@@ -158,9 +168,7 @@ func ToggleInfo(hide ...struct{}) {
 		infoID("")
 
 		infoContext(true, struct{}{})
-
-		app.UI.Region.Clear().
-			AddItem(app.UI.Pages, 0, 1, true)
+		updateSidePane()
 
 		if player.region.GetItemCount() > 2 {
 			player.region.RemoveItemIndex(1)
@@ -171,17 +179,7 @@ func ToggleInfo(hide ...struct{}) {
 
 	if !player.toggle.Load() && player.status.Load() {
 		player.toggle.Store(true)
-
-		property := player.property.SetContext(theme.ThemeContextPlayerInfo)
-		box := theme.NewBox(property)
-		vbox := app.VerticalLine(property.SetItem(theme.ThemeBorder))
-
-		app.UI.Region.Clear().
-			AddItem(player.region, 0, 1, false).
-			AddItem(box, 1, 0, false).
-			AddItem(vbox, 1, 0, false).
-			AddItem(box, 1, 0, false).
-			AddItem(app.UI.Pages, 0, 2, true)
+		updateSidePane()
 
 		Resize(0, struct{}{})
 
@@ -191,12 +189,33 @@ func ToggleInfo(hide ...struct{}) {
 	}
 }
 
+// updateSidePane shows the player information or the queue within
+// the side pane, according to the player's state and the configuration.
+func updateSidePane() {
+	switch {
+	case player.toggle.Load():
+		app.SetSidePane(player.region, player.property.SetContext(theme.ThemeContextPlayerInfo))
+
+	case player.status.Load() && cmd.GetOptionValue("side-pane") == "queue":
+		if player.queue.modal.Open {
+			player.queue.modal.Exit(false)
+		}
+
+		app.SetSidePane(player.queue.modal.Flex, player.queue.ThemeProperty())
+
+	default:
+		app.SetSidePane(nil, player.property)
+	}
+}
+
 // Hide hides the player.
 func Hide() {
 	if player.setting.Load() {
 		return
 	}
 
+	endTrack(false)
+
 	Context(true)
 	player.queue.Context(true)
 
@@ -206,8 +225,7 @@ func Hide() {
 	app.UI.QueueUpdateDraw(func() {
 		player.seeker.Hide()
 		ToggleInfo(struct{}{})
-		app.ResizeModal()
-		app.UI.Layout.RemoveItem(player.flex)
+		app.HidePlayer()
 	})
 
 	mp.Player().Stop()
@@ -238,7 +256,7 @@ func Resize(width int, force ...struct{}) {
 		goto ResizePlayer
 	}
 
-	if width == player.width {
+	if width == player.width && app.IsCompactPlayer() == player.compact {
 		return
 	}
 
@@ -246,6 +264,7 @@ ResizePlayer:
 	sendPlayerEvents()
 
 	player.width = width
+	player.compact = app.IsCompactPlayer()
 }
 
 // ParseQuery parses the play-audio or play-video commandline
@@ -379,6 +398,9 @@ func Keybindings(event *tcell.EventKey) *tcell.EventKey {
 
 	case keybinding.KeyPlayerSeekCustom:
 		player.seeker.Show()
+
+	case keybinding.KeyPlayerCast:
+		go showRenderers()
 	}
 
 	return event
@@ -526,18 +548,35 @@ func renderPlayer() {
 	var width int
 	var marker string
 
+	compact := app.IsCompactPlayer()
+	title := player.queue.GetTitle()
+
 	app.UI.Lock()
 	_, _, width, _ = player.desc.GetRect()
 	if m := player.queue.marker; m != nil {
 		marker = m.Text
 	}
+	if compact {
+		player.flex.ResizeItem(player.title, 0, 0)
+	} else {
+		player.flex.ResizeItem(player.title, 1, 0)
+	}
 	app.UI.Unlock()
 
+	if compact {
+		title = compactTitle(title, width/3)
+		width -= len([]rune(title)) + 2
+	}
+
 	title, desc, states := updateProgressAndInfo(
-		player.queue.GetTitle(),
+		title,
 		marker,
 		width-10,
 	)
+	if compact {
+		desc, title = title+"  "+desc, ""
+	}
+
 	player.title.SetText(title)
 	player.desc.SetText(desc)
 	app.DrawPrimitives(player.flex)
@@ -547,6 +586,19 @@ func renderPlayer() {
 	player.mutex.Unlock()
 }
 
+// compactTitle truncates the title to fit within the provided width.
+func compactTitle(title string, width int) string {
+	runes := []rune(title)
+	if len(runes) <= width {
+		return title
+	}
+	if width <= 3 {
+		return ""
+	}
+
+	return string(runes[:width-3]) + "..."
+}
+
 // changeImageQuality sets or displays options to change the quality of the image
 // in the player information area.
 //
@@ -826,8 +878,16 @@ func mediaEventHandler(event mp.MediaEvent) {
 	case mp.EventLoading:
 		player.queue.MarkPlayingEntry(EntryLoading)
 
+	case mp.EventStart:
+		startTrack()
+
+	case mp.EventPause:
+		pauseTrack()
+
 	case mp.EventEnd:
 		player.queue.MarkPlayingEntry(EntryStopped)
+
+		endTrack(true)
 		player.queue.AutoPlay(false)
 
 	case mp.EventError:
@@ -965,6 +1025,10 @@ func updateProgressAndInfo(title, marker string, width int) (string, string, []s
 	builder.Format(theme.ThemeMediaType, "mediatype", "(%s) ", player.queue.GetMediaType())
 	builder.AppendText(marker)
 
+	if renderer, ok := mp.CastRenderer(); ok {
+		title += " (on " + tview.Escape(renderer.Name) + ")"
+	}
+
 	title = theme.SetTextStyle(
 		"title",
 		title,
@@ -975,6 +1039,41 @@ func updateProgressAndInfo(title, marker string, width int) (string, string, []s
 	return title, builder.Get(), states
 }
 
+// progressMouseHandler seeks to the position within the track
+// which corresponds to the position clicked on the progress bar.
+func progressMouseHandler(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	x, y := event.Position()
+	if !player.desc.InRect(x, y) {
+		return action, event
+	}
+	if action != tview.MouseLeftClick {
+		return action, nil
+	}
+
+	region := app.MouseRegion(player.desc, x, y)
+	if name, _, _ := theme.GetThemeRegion(region); name != "progress" {
+		return action, nil
+	}
+
+	rectX, _, _, _ := player.desc.GetInnerRect()
+
+	column := x - rectX - player.desc.GetRegionStart(region) - 2
+	width := len([]rune(player.desc.GetRegionText(region))) - 4
+	if width <= 0 || column < 0 || column > width {
+		return action, nil
+	}
+
+	duration := mp.Player().Duration()
+	if duration <= 0 {
+		return action, nil
+	}
+
+	mp.Player().SetPosition(duration * int64(column) / int64(width))
+	sendPlayerEvents()
+
+	return action, nil
+}
+
 // sendPlayingStatus sends status events to the player.
 // If playing is true, the player is shown and vice-versa.
 func sendPlayingStatus(playing bool) {
@@ -28,8 +28,8 @@ import (
 
 // Queue describes the media queue.
 type Queue struct {
-	init, moveMode bool
-	prevrow        int
+	init, moveMode   bool
+	prevrow, dragRow int
 
 	status chan struct{}
 
@@ -92,6 +92,7 @@ func (q *Queue) Setup() {
 	defer q.tabsHandler()
 
 	q.store = deque.New[*QueueData](100)
+	q.dragRow = -1
 
 	q.status = make(chan struct{}, 100)
 
@@ -105,6 +106,7 @@ func (q *Queue) Setup() {
 	q.table.SetSelectable(true, false)
 	q.table.SetInputCapture(q.Keybindings)
 	q.table.SetSelectionChangedFunc(q.selectorHandler)
+	q.table.SetMouseCapture(q.mouseHandler)
 	q.table.SetFocusFunc(func() {
 		app.SetContextMenu(keybinding.KeyContextQueue, q.table)
 	})
@@ -131,22 +133,43 @@ func (q *Queue) Setup() {
 
 // Show shows the player queue.
 func (q *Queue) Show() {
-	if q.IsOpen() || q.Count() == 0 || !player.setting.Load() {
+	if q.Count() == 0 || !player.setting.Load() {
 		return
 	}
 
-	q.modal.Show(true)
+	switch {
+	case q.isDocked():
+		app.UI.SetFocus(q.modal.Flex)
+
+	case q.IsOpen():
+		return
+
+	default:
+		q.modal.Show(true)
+	}
+
 	q.sendStatus()
 }
 
-// Hide hides the player queue.
+// Hide hides the player queue. If the queue is shown
+// within the side pane, the focus is moved to the current view.
 func (q *Queue) Hide() {
+	if q.isDocked() {
+		app.SetPrimaryFocus()
+		return
+	}
+
 	q.modal.Exit(false)
 }
 
 // IsOpen returns whether the queue is open.
 func (q *Queue) IsOpen() bool {
-	return q.modal != nil && q.modal.Open
+	return q.modal != nil && (q.modal.Open || q.isDocked())
+}
+
+// isDocked returns whether the queue is shown within the side pane.
+func (q *Queue) isDocked() bool {
+	return q.modal != nil && app.SidePane() == q.modal.Flex
 }
 
 // IsQueueShown returns whether the queue page is shown.
@@ -179,6 +202,8 @@ func (q *Queue) Add(video inv.VideoData, audio bool, uri ...[2]string) {
 	timestamp := video.Timestamp
 	video.MediaType = media
 
+	_, local := inv.LocalMedia(video.VideoID, audio)
+
 	q.SetData(count, QueueData{
 		Columns: [QueueColumnSize]*tview.TableCell{
 			theme.NewTableCell(
@@ -223,9 +248,9 @@ func (q *Queue) Add(video inv.VideoData, audio bool, uri ...[2]string) {
 			theme.NewTableCell(
 				theme.ThemeContextQueue,
 				theme.ThemeMediaType,
-				media,
+				mediaMarkerText(media, local),
 			).
-				SetMaxWidth(5).
+				SetMaxWidth(13).
 				SetSelectable(true),
 			theme.NewTableCell(
 				theme.ThemeContextQueue,
@@ -352,6 +377,27 @@ func (q *Queue) AutoPlay(force bool) {
 	q.Next()
 }
 
+// hasNext returns whether another entry will be played
+// once the current entry has finished playing.
+func (q *Queue) hasNext() bool {
+	if q.GetRepeatMode() == mp.RepeatModePlaylist {
+		return true
+	}
+
+	position, count := q.Position(), q.Count()
+	if !q.shuffle.Load() {
+		return position+1 < count
+	}
+
+	for i := 0; i < count; i++ {
+		if data, ok := q.Get(i); ok && i != position && !data.HasPlayed {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Play plays the entry at the current queue position.
 func (q *Queue) Play(norender ...struct{}) {
 	go func() {
@@ -377,14 +423,36 @@ func (q *Queue) Play(norender ...struct{}) {
 		sendPlayerEvents()
 		Show()
 
-		video, uri, err := inv.RenewVideoURI(q.playctx, data.URI, data.Reference, data.Audio)
-		if err != nil {
-			if !errors.Is(err, context.Canceled) {
-				q.MarkPlayingEntry(EntryStopped)
-				app.ShowError(fmt.Errorf("Player: Cannot get media URI for %s", data.Reference.Title))
+		video, uri, local := data.Reference, [2]string{}, false
+		if !video.LiveNow && !mp.Casting() {
+			uri, local = inv.LocalMedia(video.VideoID, data.Audio)
+		}
+		if local {
+			if info, err := inv.ReadDownloadInfo(uri[0]); err == nil {
+				info.MediaType, info.Timestamp = video.MediaType, video.Timestamp
+				video = info
 			}
+		}
+		if !local {
+			var err error
 
-			return
+			video, uri, err = inv.RenewVideoURI(q.playctx, data.URI, data.Reference, data.Audio)
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					q.MarkPlayingEntry(EntryStopped)
+					app.ShowError(fmt.Errorf("Player: Cannot get media URI for %s", data.Reference.Title))
+				}
+
+				return
+			}
+
+			if mp.Casting() {
+				if uri[1] != "" {
+					uri = [2]string{inv.MuxedVideoURI(video)}
+				}
+
+				mp.Player().Set("mime-type", inv.MediaMimeType(video, uri[0]))
+			}
 		}
 
 		q.SetReference(q.Position(), video, struct{}{})
@@ -742,11 +810,13 @@ func (q *Queue) MarkEntryMediaType(key keybinding.Key) {
 
 	position := mp.Player().Position()
 
+	_, local := inv.LocalMedia(data.Reference.VideoID, audio)
+
 	data.Audio = audio
 	data.Timestamp = &position
 	data.Columns[QueueMediaMarker].SetText(
 		theme.SetTextStyle(
-			"mediatype", media, theme.ThemeContextQueue, theme.ThemeMediaType),
+			"mediatype", mediaMarkerText(media, local), theme.ThemeContextQueue, theme.ThemeMediaType),
 	)
 
 	if pos == q.Position() {
@@ -1098,9 +1168,16 @@ func (q *Queue) play() {
 // remove handles the 'd' key within the queue.
 // It deletes the currently selected queue item.
 func (q *Queue) remove() {
-	rows := q.table.GetRowCount() - 1
 	row, _ := q.table.GetSelection()
 
+	q.removeEntry(row)
+}
+
+// removeEntry deletes the queue item at the specified row, and
+// plays or selects the appropriate entry after the deletion.
+func (q *Queue) removeEntry(row int) {
+	rows := q.table.GetRowCount() - 1
+
 	q.Delete(row)
 
 	switch {
@@ -1143,6 +1220,38 @@ func (q *Queue) move() {
 	q.table.Select(q.prevrow, 0)
 }
 
+// mouseHandler handles dragging entries within the queue to move them.
+func (q *Queue) mouseHandler(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	x, y := event.Position()
+	if !q.table.InRect(x, y) || q.moveMode {
+		return action, event
+	}
+
+	_, tableY, _, _ := q.table.GetInnerRect()
+	rowOffset, _ := q.table.GetOffset()
+
+	row := y - tableY + rowOffset
+	if row < 0 || row >= q.Count() {
+		q.dragRow = -1
+		return action, event
+	}
+
+	switch action {
+	case tview.MouseLeftDown:
+		q.dragRow = row
+
+	case tview.MouseLeftUp:
+		if q.dragRow >= 0 && q.dragRow != row {
+			q.Move(q.dragRow, row)
+			q.table.Select(row, 0)
+		}
+
+		q.dragRow = -1
+	}
+
+	return action, event
+}
+
 // selectorHandler checks whether the move mode is enabled or not,
 // and displays the appropriate selector indicator within the queue.
 func (q *Queue) selectorHandler(row, col int) {
@@ -1290,6 +1399,15 @@ func (q *Queue) sendStatus() {
 	}
 }
 
+// mediaMarkerText returns the text for the media type marker of a queue entry.
+func mediaMarkerText(media string, local bool) string {
+	if local {
+		media += " (local)"
+	}
+
+	return media
+}
+
 // AttachableReference returns an attachable reference to the video item.
 func AttachableReference(v inv.VideoData) inv.SearchData {
 	return inv.SearchData{
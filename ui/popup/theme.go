@@ -1,6 +1,10 @@
 package popup
 
 import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+
 	"github.com/darkhz/invidtui/cmd"
 	"github.com/darkhz/invidtui/ui/app"
 	"github.com/darkhz/invidtui/ui/keybinding"
@@ -19,7 +23,7 @@ func ShowThemes() {
 
 	app.UI.QueueUpdateDraw(func() {
 		app.UI.FileBrowser.Show(
-			"Select theme:",
+			"Select theme or color scheme:",
 			ApplyTheme,
 			app.FileBrowserOptions{
 				SetDir:    dir,
@@ -29,8 +33,29 @@ func ShowThemes() {
 	})
 }
 
-// ApplyTheme applies the theme from the provided file.
+// ApplyTheme applies the theme from the provided file. If the file is a
+// color scheme, it is converted into a theme before being applied.
 func ApplyTheme(themePath string) {
+	if filepath.Ext(themePath) != ".theme" {
+		app.ShowInfo("Importing color scheme", true)
+
+		importedPath, err := theme.ImportScheme(themePath, "", false)
+		if errors.Is(err, fs.ErrExist) {
+			if app.UI.FileBrowser.Query("Overwrite existing theme (y/n)?", validateOverwrite, 1) != "y" {
+				app.ShowInfo("", false)
+				return
+			}
+
+			importedPath, err = theme.ImportScheme(themePath, "", true)
+		}
+		if err != nil {
+			showErrorModal(err)
+			return
+		}
+
+		themePath = importedPath
+	}
+
 	app.ShowInfo("Applying theme", true)
 
 	if err := theme.ParseFile(themePath); err != nil {
@@ -46,6 +71,19 @@ func ApplyTheme(themePath string) {
 	app.ShowInfo("Theme applied", false)
 }
 
+// validateOverwrite validates the reply to the theme overwrite confirmation.
+func validateOverwrite(text string, reply chan string) {
+	if text != "y" && text != "n" {
+		return
+	}
+
+	select {
+	case reply <- text:
+
+	default:
+	}
+}
+
 // showErrorModal shows a modal with an error message.
 func showErrorModal(err error) {
 	var modal *app.Modal
@@ -0,0 +1,570 @@
+package popup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/ui/keybinding"
+	"github.com/darkhz/invidtui/ui/theme"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ThemeEditor describes the layout of the theme editor popup.
+type ThemeEditor struct {
+	context theme.ThemeContext
+	item    theme.ThemeItem
+	values  map[theme.ThemeContext]map[theme.ThemeItem]string
+
+	modal           *app.Modal
+	flex            *tview.Flex
+	contexts, items *tview.Table
+	preview         *tview.TextView
+
+	picker ColorPicker
+}
+
+// ColorPicker describes the layout of the color picker popup.
+type ColorPicker struct {
+	foreground bool
+
+	modal *app.Modal
+	flex  *tview.Flex
+	table *tview.Table
+	input *tview.InputField
+
+	attributes *app.Modal
+}
+
+var editor ThemeEditor
+
+// ShowThemeEditor shows a popup to edit the items of the current theme.
+func ShowThemeEditor() {
+	if editor.modal != nil {
+		if editor.modal.Open {
+			return
+		}
+
+		goto Render
+	}
+
+	setupThemeEditor()
+
+Render:
+	editor.values = theme.ThemeValues()
+	renderContexts()
+
+	editor.modal.Show(false)
+}
+
+// setupThemeEditor sets up the theme editor popup.
+func setupThemeEditor() {
+	property := theme.ThemeProperty{
+		Context: theme.ThemeContextEditor,
+		Item:    theme.ThemePopupBackground,
+	}
+
+	editor.contexts = theme.NewTable(property)
+	editor.contexts.SetSelectable(true, false)
+	editor.contexts.SetInputCapture(themeEditorKeybindings)
+	editor.contexts.SetSelectionChangedFunc(func(row, col int) {
+		if context, ok := editor.contexts.GetCell(row, 0).GetReference().(theme.ThemeContext); ok {
+			editor.context = context
+			renderItems()
+		}
+	})
+	editor.contexts.SetFocusFunc(func() {
+		app.SetContextMenu(keybinding.KeyContextThemeEditor, editor.contexts)
+	})
+
+	editor.items = theme.NewTable(property)
+	editor.items.SetSelectable(true, false)
+	editor.items.SetInputCapture(themeEditorKeybindings)
+	editor.items.SetSelectionChangedFunc(func(row, col int) {
+		if item, ok := editor.items.GetCell(row, 0).GetReference().(theme.ThemeItem); ok {
+			editor.item = item
+			renderPreview()
+		}
+	})
+	editor.items.SetFocusFunc(func() {
+		app.SetContextMenu(keybinding.KeyContextThemeEditor, editor.items)
+	})
+
+	editor.preview = theme.NewTextView(property)
+	editor.preview.SetTextAlign(tview.AlignCenter)
+
+	tables := theme.NewFlex(property).
+		SetDirection(tview.FlexColumn).
+		AddItem(editor.contexts, 20, 0, true).
+		AddItem(theme.NewBox(property), 1, 0, false).
+		AddItem(editor.items, 0, 1, false)
+
+	editor.flex = theme.NewFlex(property).
+		SetDirection(tview.FlexRow).
+		AddItem(tables, 0, 10, true).
+		AddItem(app.HorizontalLine(property.SetItem(theme.ThemePopupBorder)), 1, 0, false).
+		AddItem(editor.preview, 1, 0, false)
+
+	editor.modal = app.NewModal("theme_editor", "Edit Theme", editor.flex, 40, 80, property)
+}
+
+// themeEditorKeybindings defines the keybindings for the theme editor.
+func themeEditorKeybindings(event *tcell.EventKey) *tcell.EventKey {
+	operation := keybinding.KeyOperation(event, keybinding.KeyContextThemeEditor)
+
+	switch operation {
+	case keybinding.KeySwitch:
+		if editor.contexts.HasFocus() {
+			app.UI.SetFocus(editor.items)
+		} else {
+			app.UI.SetFocus(editor.contexts)
+		}
+
+		return nil
+
+	case keybinding.KeySelect:
+		if editor.contexts.HasFocus() {
+			app.UI.SetFocus(editor.items)
+			return nil
+		}
+
+		fallthrough
+
+	case keybinding.KeyThemeEditorForeground, keybinding.KeyThemeEditorBackground:
+		if editor.item != "" {
+			showColorPicker(operation != keybinding.KeyThemeEditorBackground)
+		}
+
+		return nil
+
+	case keybinding.KeyThemeEditorAttributes:
+		if editor.item != "" {
+			showAttributes()
+		}
+
+		return nil
+
+	case keybinding.KeyThemeEditorClear:
+		setEditorValue("")
+		return nil
+
+	case keybinding.KeyThemeEditorSave:
+		dir, err := cmd.GetConfigDir("themes")
+		if err != nil {
+			app.ShowError(err)
+			return nil
+		}
+
+		app.UI.FileBrowser.Show(
+			"Save theme as:",
+			saveTheme,
+			app.FileBrowserOptions{
+				SetDir:    dir,
+				ResetPath: true,
+			},
+		)
+
+		return nil
+
+	case keybinding.KeyClose:
+		editor.modal.Exit(false)
+	}
+
+	return event
+}
+
+// renderContexts renders the theme contexts within the theme editor.
+func renderContexts() {
+	contexts := make([]string, 0, len(theme.ThemeScopes))
+	for context := range theme.ThemeScopes {
+		contexts = append(contexts, string(context))
+	}
+	sort.Strings(contexts)
+
+	editor.contexts.Clear()
+
+	for row, context := range contexts {
+		editor.contexts.SetCell(row, 0, theme.NewTableCell(
+			theme.ThemeContextEditor,
+			theme.ThemeName,
+			context,
+		).
+			SetExpansion(1).
+			SetReference(theme.ThemeContext(context)),
+		)
+	}
+
+	editor.contexts.ScrollToBeginning()
+	editor.contexts.Select(0, 0)
+}
+
+// renderItems renders the items of the selected context within the theme editor.
+func renderItems() {
+	items := make([]string, 0, len(theme.ThemeScopes[editor.context]))
+	for item := range theme.ThemeScopes[editor.context] {
+		items = append(items, string(item))
+	}
+	sort.Strings(items)
+
+	selected, _ := editor.items.GetSelection()
+	editor.items.Clear()
+
+	for row, item := range items {
+		value := editor.values[editor.context][theme.ThemeItem(item)]
+		if value == "" && editor.context != theme.ThemeContextApp {
+			if global := editor.values[theme.ThemeContextApp][theme.ThemeItem(item)]; global != "" {
+				value = "(App) " + global
+			}
+		}
+
+		editor.items.SetCell(row, 0, theme.NewTableCell(
+			theme.ThemeContextEditor,
+			theme.ThemeDescription,
+			item,
+		).
+			SetReference(theme.ThemeItem(item)),
+		)
+
+		editor.items.SetCell(row, 1, theme.NewTableCell(
+			theme.ThemeContextEditor,
+			theme.ThemeText,
+			tview.Escape(value),
+		).
+			SetExpansion(1),
+		)
+	}
+
+	if selected >= len(items) {
+		selected = 0
+	}
+
+	editor.items.Select(selected, 0)
+	if len(items) == 0 {
+		editor.item = ""
+		renderPreview()
+	}
+}
+
+// renderPreview renders a preview of the selected item.
+func renderPreview() {
+	if editor.item == "" {
+		editor.preview.Clear()
+		return
+	}
+
+	editor.preview.SetText(theme.SetTextStyle(
+		"preview",
+		fmt.Sprintf("Preview of %s -> %s", editor.context, editor.item),
+		editor.context,
+		editor.item,
+	))
+}
+
+// setEditorValue sets and applies the parameter for the selected item.
+func setEditorValue(value string) {
+	if editor.item == "" {
+		return
+	}
+
+	if err := theme.SetThemeValue(editor.context, editor.item, value); err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	if editor.values[editor.context] == nil {
+		editor.values[editor.context] = make(map[theme.ThemeItem]string)
+	}
+	if value == "" {
+		delete(editor.values[editor.context], editor.item)
+	} else {
+		editor.values[editor.context][editor.item] = value
+	}
+
+	theme.UpdateThemeVersion()
+
+	renderItems()
+	renderPreview()
+}
+
+// updateEditorValue modifies the parameter for the selected item.
+func updateEditorValue(update func(attrs []string, fg, bg string) ([]string, string, string)) {
+	setEditorValue(theme.JoinThemeValue(
+		update(theme.SplitThemeValue(editor.values[editor.context][editor.item])),
+	))
+}
+
+// saveTheme saves the edited theme to the provided file, and applies it.
+func saveTheme(file string) {
+	var saved bool
+
+	if filepath.Ext(file) == "" {
+		file += ".theme"
+	}
+
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	values := theme.ThemeValues()
+
+	app.UI.FileBrowser.SaveFile(file, func(flags int, appendToFile bool) (string, int, error) {
+		if appendToFile {
+			return "", flags, fmt.Errorf("Theme: Cannot append to an existing theme file")
+		}
+
+		saved = true
+
+		return theme.FormatTheme(name, values), flags | os.O_TRUNC, nil
+	})
+	if !saved {
+		return
+	}
+
+	if err := theme.ParseFile(file); err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	app.UI.QueueUpdateDraw(func() {
+		theme.UpdateThemeVersion()
+	})
+}
+
+// showColorPicker shows a popup to select the foreground
+// or background color for the selected item.
+func showColorPicker(foreground bool) {
+	picker := &editor.picker
+	picker.foreground = foreground
+
+	if picker.modal == nil {
+		setupColorPicker()
+	}
+
+	title := "Background"
+	if foreground {
+		title = "Foreground"
+	}
+	picker.modal.Title.SetText(theme.SetTextStyle(
+		"title", title+" color",
+		theme.ThemeContextEditor, theme.ThemeTitle,
+	))
+
+	picker.input.SetText("")
+	picker.modal.Show(false)
+
+	colorFilter("")
+}
+
+// setupColorPicker sets up the color picker popup.
+func setupColorPicker() {
+	picker := &editor.picker
+
+	property := theme.ThemeProperty{
+		Context: theme.ThemeContextEditor,
+		Item:    theme.ThemePopupBackground,
+	}
+
+	picker.table = theme.NewTable(property)
+	picker.table.SetSelectable(true, false)
+	picker.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch keybinding.KeyOperation(event, keybinding.KeyContextCommon) {
+		case keybinding.KeySelect:
+			row, _ := picker.table.GetSelection()
+			if color, ok := picker.table.GetCell(row, 1).GetReference().(string); ok {
+				exitPicker(picker.modal)
+				setColor(color)
+			}
+
+			return nil
+
+		case keybinding.KeyQuery:
+			app.UI.SetFocus(picker.input)
+			return nil
+
+		case keybinding.KeyClose:
+			exitPicker(picker.modal)
+			return nil
+		}
+
+		return event
+	})
+
+	picker.input = theme.NewInputField(property, "Filter or #rrggbb:")
+	picker.input.SetChangedFunc(colorFilter)
+	picker.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch keybinding.KeyOperation(event, keybinding.KeyContextCommon) {
+		case keybinding.KeySelect, keybinding.KeyClose:
+			app.UI.SetFocus(picker.table)
+		}
+
+		return event
+	})
+
+	picker.flex = theme.NewFlex(property).
+		SetDirection(tview.FlexRow).
+		AddItem(picker.table, 0, 10, true).
+		AddItem(app.HorizontalLine(property.SetItem(theme.ThemePopupBorder)), 1, 0, false).
+		AddItem(picker.input, 1, 0, false)
+
+	picker.modal = app.NewModal("theme_color", "Color", picker.flex, 30, 40, property)
+}
+
+// exitPicker closes the provided picker popup, and focuses the theme items.
+func exitPicker(modal *app.Modal) {
+	modal.Exit(false)
+	app.UI.SetFocus(editor.items)
+}
+
+// setColor sets the foreground or background color for the selected item.
+func setColor(color string) {
+	updateEditorValue(func(attrs []string, fg, bg string) ([]string, string, string) {
+		if editor.picker.foreground {
+			return attrs, color, bg
+		}
+
+		return attrs, fg, color
+	})
+}
+
+// colorFilter lists the named, hex and 256-palette colors which
+// match the provided text within the color picker.
+func colorFilter(text string) {
+	var row int
+
+	table := editor.picker.table
+	table.Clear()
+
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	colors := []string{"default"}
+	if strings.HasPrefix(text, "#") && len(text) == 7 && tcell.GetColor(text) != tcell.ColorDefault {
+		colors = []string{text}
+	}
+
+	names := make([]string, 0, len(tcell.ColorNames))
+	for name := range tcell.ColorNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	colors = append(colors, names...)
+
+	for i := 0; i < 256; i++ {
+		colors = append(colors, "color"+strconv.Itoa(i))
+	}
+
+	for _, name := range colors {
+		if text != "" && !strings.HasPrefix(text, "#") && !strings.Contains(name, text) {
+			continue
+		}
+
+		table.SetCell(row, 0, tview.NewTableCell("    ").
+			SetBackgroundColor(pickerColor(name)).
+			SetSelectable(false),
+		)
+
+		table.SetCell(row, 1, theme.NewTableCell(
+			theme.ThemeContextEditor,
+			theme.ThemeText,
+			" "+name,
+		).
+			SetExpansion(1).
+			SetReference(name),
+		)
+
+		row++
+	}
+
+	table.ScrollToBeginning()
+	table.Select(0, 1)
+}
+
+// pickerColor returns the color for the provided color name.
+func pickerColor(name string) tcell.Color {
+	switch {
+	case name == "default":
+		return tcell.ColorDefault
+
+	case name == "black":
+		return tcell.Color16
+
+	case strings.HasPrefix(name, "color"):
+		index, err := strconv.Atoi(strings.TrimPrefix(name, "color"))
+		if err == nil {
+			return tcell.PaletteColor(index)
+		}
+	}
+
+	return tcell.GetColor(name)
+}
+
+// showAttributes shows a popup to toggle the attributes for the selected item.
+func showAttributes() {
+	picker := &editor.picker
+
+	if picker.attributes == nil {
+		picker.attributes = app.NewModal("theme_attributes", "Attributes", nil, len(theme.ThemeAttributes)+4, 30, theme.ThemeProperty{
+			Context: theme.ThemeContextEditor,
+			Item:    theme.ThemePopupBackground,
+		})
+		picker.attributes.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch keybinding.KeyOperation(event, keybinding.KeyContextCommon) {
+			case keybinding.KeySelect:
+				row, _ := picker.attributes.Table.GetSelection()
+				toggleAttribute(theme.ThemeAttributes[row])
+				renderAttributes()
+
+				return nil
+
+			case keybinding.KeyClose:
+				exitPicker(picker.attributes)
+				return nil
+			}
+
+			return event
+		})
+	}
+
+	renderAttributes()
+	picker.attributes.Table.Select(0, 0)
+	picker.attributes.Show(false)
+}
+
+// renderAttributes renders the attributes of the selected item.
+func renderAttributes() {
+	table := editor.picker.attributes.Table
+	attrs, _, _ := theme.SplitThemeValue(editor.values[editor.context][editor.item])
+
+	for row, attribute := range theme.ThemeAttributes {
+		marker := "[ ]"
+		for _, attr := range attrs {
+			if attr == attribute {
+				marker = "[x]"
+				break
+			}
+		}
+
+		table.SetCell(row, 0, theme.NewTableCell(
+			theme.ThemeContextEditor,
+			theme.ThemeText,
+			tview.Escape(marker)+" "+attribute,
+		).
+			SetExpansion(1),
+		)
+	}
+}
+
+// toggleAttribute toggles the attribute for the selected item.
+func toggleAttribute(attribute string) {
+	updateEditorValue(func(attrs []string, fg, bg string) ([]string, string, string) {
+		for i, attr := range attrs {
+			if attr == attribute {
+				return append(attrs[:i], attrs[i+1:]...), fg, bg
+			}
+		}
+
+		return append(attrs, attribute), fg, bg
+	})
+}
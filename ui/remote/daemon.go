@@ -0,0 +1,326 @@
+//go:build !windows
+// +build !windows
+
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/platform"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Daemon describes the daemon's attach server.
+type Daemon struct {
+	path     string
+	listener net.Listener
+	client   *attachedTty
+
+	mutex sync.Mutex
+}
+
+// attachedTty describes a terminal of a client which is attached to the daemon.
+// It implements the tcell.Tty interface, so that a screen can be drawn onto it.
+type attachedTty struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	size   tcell.WindowSize
+	resize func()
+
+	input   chan []byte
+	pending []byte
+	drain   chan struct{}
+	closed  chan struct{}
+
+	mutex sync.Mutex
+}
+
+var daemon Daemon
+
+// StartDaemon starts listening for clients to attach to the daemon, if enabled.
+func StartDaemon() error {
+	if !cmd.IsOptionEnabled("daemon") {
+		return nil
+	}
+
+	dir, err := cmd.GetConfigDir("")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, cmd.DaemonSocket)
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Daemon: Cannot remove stale socket: %w", err)
+	}
+
+	listener, err := platform.ListenSocket(path)
+	if err != nil {
+		return fmt.Errorf("Daemon: Cannot listen on socket: %w", err)
+	}
+
+	daemon.mutex.Lock()
+	daemon.path = path
+	daemon.listener = listener
+	daemon.mutex.Unlock()
+
+	go daemon.accept()
+
+	return nil
+}
+
+// Detach detaches the currently attached client from the daemon.
+// It returns false if no client is attached.
+func Detach() bool {
+	daemon.mutex.Lock()
+	client := daemon.client
+	daemon.client = nil
+	daemon.mutex.Unlock()
+
+	if client == nil {
+		return false
+	}
+
+	go func() {
+		app.SetScreen(app.NewDetachedScreen())
+		client.conn.Close()
+	}()
+
+	return true
+}
+
+// stopDaemon stops the daemon's attach server.
+func stopDaemon() {
+	daemon.mutex.Lock()
+	defer daemon.mutex.Unlock()
+
+	if daemon.listener == nil {
+		return
+	}
+
+	daemon.listener.Close()
+	os.Remove(daemon.path)
+
+	if daemon.client != nil {
+		daemon.client.conn.Close()
+	}
+
+	daemon.listener = nil
+}
+
+// accept accepts connections from clients, and attaches them to the daemon.
+func (d *Daemon) accept() {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go d.attach(conn)
+	}
+}
+
+// attach draws the application onto the client's terminal. Any previously
+// attached client is detached.
+func (d *Daemon) attach(conn net.Conn) {
+	var header cmd.AttachHeader
+
+	reader := bufio.NewReader(conn)
+
+	line, err := reader.ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &header)
+	}
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	tty := &attachedTty{
+		conn:   conn,
+		reader: reader,
+		size:   tcell.WindowSize{Width: header.Width, Height: header.Height},
+		input:  make(chan []byte, 10),
+		drain:  make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+
+	info, err := tcell.LookupTerminfo(header.Term)
+	if err != nil {
+		fmt.Fprintf(conn, "Daemon: Unsupported terminal %q\r\n", header.Term)
+		conn.Close()
+		return
+	}
+
+	screen, err := tcell.NewTerminfoScreenFromTtyTerminfo(tty, info)
+	if err != nil {
+		fmt.Fprintf(conn, "Daemon: Cannot create screen: %s\r\n", err)
+		conn.Close()
+		return
+	}
+
+	go tty.readFrames()
+
+	d.mutex.Lock()
+	previous := d.client
+	d.client = tty
+	d.mutex.Unlock()
+
+	app.SetScreen(screen)
+	if previous != nil {
+		previous.conn.Close()
+	}
+
+	<-tty.closed
+
+	d.mutex.Lock()
+	current := d.client == tty
+	if current {
+		d.client = nil
+	}
+	d.mutex.Unlock()
+
+	if current {
+		app.SetScreen(app.NewDetachedScreen())
+	}
+}
+
+// readFrames reads the input and resize frames sent by the client.
+func (t *attachedTty) readFrames() {
+	defer close(t.closed)
+
+	for {
+		frameType, payload, err := cmd.ReadAttachFrame(t.reader)
+		if err != nil {
+			return
+		}
+
+		switch frameType {
+		case cmd.AttachFrameInput:
+			select {
+			case t.input <- payload:
+
+			case <-t.drained():
+			}
+
+		case cmd.AttachFrameResize:
+			width, height, ok := cmd.ParseResizePayload(payload)
+			if !ok {
+				continue
+			}
+
+			t.mutex.Lock()
+			t.size.Width, t.size.Height = width, height
+			resize := t.resize
+			t.mutex.Unlock()
+
+			if resize != nil {
+				resize()
+			}
+		}
+	}
+}
+
+// Start starts reading input from the client.
+func (t *attachedTty) Start() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	select {
+	case <-t.drain:
+		t.drain = make(chan struct{})
+
+	default:
+	}
+
+	return nil
+}
+
+// Stop stops reading input from the client.
+func (t *attachedTty) Stop() error {
+	return nil
+}
+
+// Drain wakes up any pending reads.
+func (t *attachedTty) Drain() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	select {
+	case <-t.drain:
+
+	default:
+		close(t.drain)
+	}
+
+	return nil
+}
+
+// NotifyResize sets the function which is called when the client's terminal is resized.
+func (t *attachedTty) NotifyResize(resize func()) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.resize = resize
+}
+
+// WindowSize returns the size of the client's terminal.
+func (t *attachedTty) WindowSize() (tcell.WindowSize, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.size, nil
+}
+
+// Read reads input from the client.
+func (t *attachedTty) Read(b []byte) (int, error) {
+	if len(t.pending) > 0 {
+		n := copy(b, t.pending)
+		t.pending = t.pending[n:]
+
+		return n, nil
+	}
+
+	select {
+	case data := <-t.input:
+		n := copy(b, data)
+		t.pending = data[n:]
+
+		return n, nil
+
+	case <-t.drained():
+		return 0, io.EOF
+
+	case <-t.closed:
+		return 0, io.EOF
+	}
+}
+
+// drained returns a channel which is closed when the input is drained.
+func (t *attachedTty) drained() chan struct{} {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.drain
+}
+
+// Write writes output to the client's terminal.
+func (t *attachedTty) Write(b []byte) (int, error) {
+	return t.conn.Write(b)
+}
+
+// Close closes the connection to the client.
+func (t *attachedTty) Close() error {
+	return t.conn.Close()
+}
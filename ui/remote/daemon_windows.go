@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package remote
+
+// StartDaemon starts listening for clients to attach to the daemon.
+// It is not supported on Windows.
+func StartDaemon() error {
+	return nil
+}
+
+// Detach detaches the currently attached client from the daemon.
+func Detach() bool {
+	return false
+}
+
+// stopDaemon stops the daemon's attach server.
+func stopDaemon() {}
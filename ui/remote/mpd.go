@@ -0,0 +1,710 @@
+package remote
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/player"
+)
+
+// MPDServer describes the MPD protocol server.
+type MPDServer struct {
+	listener net.Listener
+	clients  map[*mpdClient]struct{}
+
+	tracks  string
+	version int
+
+	mutex sync.Mutex
+}
+
+// mpdClient describes a client connected to the MPD protocol server.
+type mpdClient struct {
+	conn   net.Conn
+	writer *bufio.Writer
+
+	pending map[string]struct{}
+	notify  chan struct{}
+
+	mutex sync.Mutex
+}
+
+// mpdError describes an MPD protocol error.
+type mpdError struct {
+	code    int
+	message string
+}
+
+// The MPD protocol error codes.
+const (
+	mpdErrorArg     = 2
+	mpdErrorUnknown = 5
+	mpdErrorNoExist = 50
+	mpdErrorSystem  = 52
+)
+
+// MPDVersion is the MPD protocol version reported to clients.
+const MPDVersion = "0.23.0"
+
+// mpdSubsystems lists the subsystems which can be waited upon with 'idle'.
+var mpdSubsystems = []string{"player", "mixer", "options", "playlist"}
+
+var mpd MPDServer
+
+// StartMPD starts the MPD protocol server, if an address is configured.
+func StartMPD() error {
+	address := cmd.GetOptionValue("mpd-address")
+	if address == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("MPD: Cannot listen on %s: %w", address, err)
+	}
+
+	mpd.mutex.Lock()
+	mpd.listener = listener
+	mpd.clients = make(map[*mpdClient]struct{})
+	mpd.version = 1
+	mpd.mutex.Unlock()
+
+	player.AddStateListener(mpd.changed)
+
+	go mpd.accept()
+
+	return nil
+}
+
+// stopMPD stops the MPD protocol server.
+func stopMPD() {
+	mpd.mutex.Lock()
+	defer mpd.mutex.Unlock()
+
+	if mpd.listener == nil {
+		return
+	}
+
+	mpd.listener.Close()
+	for client := range mpd.clients {
+		client.conn.Close()
+	}
+
+	mpd.listener = nil
+}
+
+// accept accepts and handles connections to the server.
+func (m *MPDServer) accept() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		client := &mpdClient{
+			conn:    conn,
+			writer:  bufio.NewWriter(conn),
+			pending: make(map[string]struct{}),
+			notify:  make(chan struct{}, 1),
+		}
+
+		m.mutex.Lock()
+		m.clients[client] = struct{}{}
+		m.mutex.Unlock()
+
+		go func() {
+			client.handle()
+
+			m.mutex.Lock()
+			delete(m.clients, client)
+			m.mutex.Unlock()
+		}()
+	}
+}
+
+// changed determines the subsystems which have changed between
+// the player states, and notifies the connected clients.
+func (m *MPDServer) changed(previous, current player.State) {
+	var subsystems []string
+
+	if previous.Status != current.Status || previous.VideoID != current.VideoID ||
+		previous.QueuePosition != current.QueuePosition ||
+		(current.Position-previous.Position) > 2 || current.Position < previous.Position {
+		subsystems = append(subsystems, "player")
+	}
+	if previous.Volume != current.Volume || previous.Muted != current.Muted {
+		subsystems = append(subsystems, "mixer")
+	}
+	if previous.Repeat != current.Repeat || previous.Shuffle != current.Shuffle {
+		subsystems = append(subsystems, "options")
+	}
+	if m.updateVersion() {
+		subsystems = append(subsystems, "playlist")
+	}
+
+	if subsystems == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for client := range m.clients {
+		client.mutex.Lock()
+		for _, subsystem := range subsystems {
+			client.pending[subsystem] = struct{}{}
+		}
+		client.mutex.Unlock()
+
+		select {
+		case client.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// updateVersion increments the version of the queue if the entries
+// within the queue have changed, and returns whether they have changed.
+func (m *MPDServer) updateVersion() bool {
+	var ids []string
+
+	for _, entry := range player.QueueEntries() {
+		ids = append(ids, entry.VideoID)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	tracks := strings.Join(ids, ",")
+	if tracks == m.tracks {
+		return false
+	}
+
+	m.tracks = tracks
+	m.version++
+
+	return true
+}
+
+// playlistVersion returns the version of the queue.
+func (m *MPDServer) playlistVersion() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.version
+}
+
+// handle reads commands from the client and writes the responses back to it.
+func (c *mpdClient) handle() {
+	var list []string
+	var listMode string
+
+	done := make(chan struct{})
+	lines := make(chan string)
+
+	defer close(done)
+	defer c.conn.Close()
+
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(c.conn)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	c.write("OK MPD %s\n", MPDVersion)
+	if c.writer.Flush() != nil {
+		return
+	}
+
+	for line := range lines {
+		switch {
+		case line == "command_list_begin", line == "command_list_ok_begin":
+			list, listMode = nil, line
+			continue
+
+		case line == "command_list_end" && listMode != "":
+			c.commandList(list, listMode == "command_list_ok_begin")
+			list, listMode = nil, ""
+
+		case listMode != "":
+			list = append(list, line)
+			continue
+
+		case line == "close":
+			return
+
+		case strings.HasPrefix(line, "idle"):
+			if !c.idle(line, lines) {
+				return
+			}
+
+		default:
+			c.commandList([]string{line}, false)
+		}
+
+		if c.writer.Flush() != nil {
+			return
+		}
+	}
+}
+
+// commandList runs a list of commands, and stops at the first command which fails.
+// If 'listOK' is set, 'list_OK' is written after each successful command.
+func (c *mpdClient) commandList(list []string, listOK bool) {
+	for i, line := range list {
+		args, err := mpdArgs(line)
+		if err == nil && len(args) == 0 {
+			err = mpdError{mpdErrorUnknown, "No command given"}
+		}
+		if err == nil {
+			err = c.run(args)
+		}
+
+		if err != nil {
+			var command string
+			if len(args) > 0 {
+				command = args[0]
+			}
+
+			var merr mpdError
+			if !errors.As(err, &merr) {
+				merr = mpdError{mpdErrorSystem, err.Error()}
+			}
+
+			c.write("ACK [%d@%d] {%s} %s\n", merr.code, i, command, merr.message)
+
+			return
+		}
+
+		if listOK {
+			c.write("list_OK\n")
+		}
+	}
+
+	c.write("OK\n")
+}
+
+// idle waits until one of the provided subsystems (or any subsystem, if none are
+// provided) changes, or until the client sends 'noidle'. It returns false if
+// the connection is closed, or if any other command is sent while waiting.
+func (c *mpdClient) idle(line string, lines chan string) bool {
+	args, err := mpdArgs(line)
+	if err != nil || args[0] != "idle" {
+		c.commandList([]string{line}, false)
+		return true
+	}
+
+	subsystems := args[1:]
+	if len(subsystems) == 0 {
+		subsystems = mpdSubsystems
+	}
+
+	for {
+		if changed := c.changes(subsystems); changed != nil {
+			for _, subsystem := range changed {
+				c.write("changed: %s\n", subsystem)
+			}
+			c.write("OK\n")
+
+			return true
+		}
+
+		select {
+		case <-c.notify:
+
+		case line, ok := <-lines:
+			if !ok || line != "noidle" {
+				return false
+			}
+
+			c.write("OK\n")
+
+			return true
+		}
+	}
+}
+
+// changes returns and clears the pending changes for the subsystems.
+func (c *mpdClient) changes(subsystems []string) []string {
+	var changed []string
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, subsystem := range subsystems {
+		if _, ok := c.pending[subsystem]; ok {
+			changed = append(changed, subsystem)
+			delete(c.pending, subsystem)
+		}
+	}
+
+	return changed
+}
+
+// run runs the command.
+//
+//gocyclo:ignore
+func (c *mpdClient) run(args []string) error {
+	var err error
+
+	command, args := args[0], args[1:]
+
+	switch command {
+	case "ping", "clearerror", "tagtypes", "binarylimit", "password",
+		"lsinfo", "listplaylists", "decoders", "consume", "crossfade", "single":
+
+	case "commands":
+		for _, name := range []string{
+			"add", "close", "commands", "currentsong", "delete", "deleteid",
+			"idle", "next", "noidle", "notcommands", "outputs", "pause",
+			"ping", "play", "playid", "playlistid", "playlistinfo", "plchanges",
+			"previous", "random", "repeat", "seekcur", "setvol", "stats",
+			"status", "stop", "urlhandlers",
+		} {
+			c.write("command: %s\n", name)
+		}
+
+	case "notcommands":
+
+	case "urlhandlers":
+		c.write("handler: https://\nhandler: http://\n")
+
+	case "outputs":
+		c.write("outputid: 0\noutputname: mpv\nplugin: mpv\noutputenabled: 1\n")
+
+	case "stats":
+		c.write("artists: 0\nalbums: 0\nsongs: %d\nuptime: 0\nplaytime: 0\ndb_playtime: 0\ndb_update: 0\n",
+			player.GetState().QueueCount,
+		)
+
+	case "status":
+		c.status()
+
+	case "currentsong":
+		state := player.GetState()
+		if state.Status == player.StatusStopped {
+			break
+		}
+
+		entries := player.QueueEntries()
+		if state.QueuePosition >= 0 && state.QueuePosition < len(entries) {
+			c.song(entries[state.QueuePosition])
+		}
+
+	case "playlistinfo", "playlistid", "plchanges":
+		start, end := 0, -1
+		if len(args) > 0 && command != "plchanges" {
+			if start, end, err = mpdRange(args[0]); err != nil {
+				return err
+			}
+		}
+
+		for _, entry := range player.QueueEntries() {
+			if entry.Position >= start && (end < 0 || entry.Position < end) {
+				c.song(entry)
+			}
+		}
+
+	case "add":
+		if len(args) == 0 {
+			return mpdError{mpdErrorArg, "Missing URI"}
+		}
+
+		err = player.QueueAdd(args[0], true)
+		if err != nil {
+			return mpdError{mpdErrorNoExist, err.Error()}
+		}
+
+	case "delete", "deleteid":
+		if len(args) == 0 {
+			return mpdError{mpdErrorArg, "Missing position"}
+		}
+
+		position, err := mpdInt(args[0])
+		if err != nil {
+			return err
+		}
+
+		return mpdResult(player.QueueDelete(position))
+
+	case "play", "playid":
+		if len(args) > 0 {
+			position, err := mpdInt(args[0])
+			if err != nil {
+				return err
+			}
+
+			return mpdResult(player.QueuePlay(position))
+		}
+
+		state := player.GetState()
+		if state.Status != player.StatusStopped || state.QueueCount == 0 {
+			err = player.Control("play")
+			break
+		}
+
+		position := state.QueuePosition
+		if position < 0 {
+			position = 0
+		}
+
+		err = player.QueuePlay(position)
+
+	case "pause":
+		operation := "toggle"
+		if len(args) > 0 {
+			switch args[0] {
+			case "0":
+				operation = "play"
+
+			case "1":
+				operation = "pause"
+
+			default:
+				return mpdError{mpdErrorArg, "Boolean (0/1) expected: " + args[0]}
+			}
+		}
+
+		err = player.Control(operation)
+
+	case "stop", "next", "previous":
+		err = player.Control(command)
+
+	case "seekcur":
+		if len(args) == 0 {
+			return mpdError{mpdErrorArg, "Missing time"}
+		}
+
+		value, perr := strconv.ParseFloat(args[0], 64)
+		if perr != nil {
+			return mpdError{mpdErrorArg, "Number expected: " + args[0]}
+		}
+
+		position := int64(value)
+		if strings.HasPrefix(args[0], "+") || strings.HasPrefix(args[0], "-") {
+			position += player.GetState().Position
+		}
+		if position < 0 {
+			position = 0
+		}
+
+		err = player.Control("seek", position)
+
+	case "setvol":
+		if len(args) == 0 {
+			return mpdError{mpdErrorArg, "Missing volume"}
+		}
+
+		volume, err := mpdInt(args[0])
+		if err != nil {
+			return err
+		}
+		if volume > 100 {
+			return mpdError{mpdErrorArg, "Invalid volume value"}
+		}
+
+		return mpdResult(player.Control("volume", int64(volume)))
+
+	case "repeat", "random":
+		if len(args) == 0 || (args[0] != "0" && args[0] != "1") {
+			return mpdError{mpdErrorArg, "Boolean (0/1) expected"}
+		}
+
+		state := player.GetState()
+		enabled := args[0] == "1"
+
+		if command == "random" {
+			if state.Shuffle != enabled {
+				err = player.Control("shuffle")
+			}
+
+			break
+		}
+
+		repeat := "off"
+		if enabled {
+			repeat = "playlist"
+		}
+
+		for i := 0; i < 3 && state.Repeat != repeat; i++ {
+			if err = player.Control("repeat"); err != nil {
+				break
+			}
+
+			state = player.GetState()
+		}
+
+	default:
+		return mpdError{mpdErrorUnknown, fmt.Sprintf("unknown command %q", command)}
+	}
+
+	return mpdResult(err)
+}
+
+// status writes the player status.
+func (c *mpdClient) status() {
+	state := player.GetState()
+	version := mpd.playlistVersion()
+
+	repeat, single := 0, 0
+	switch state.Repeat {
+	case "playlist":
+		repeat = 1
+
+	case "file":
+		repeat, single = 1, 1
+	}
+
+	random := 0
+	if state.Shuffle {
+		random = 1
+	}
+
+	status := "stop"
+	switch state.Status {
+	case player.StatusPlaying:
+		status = "play"
+
+	case player.StatusPaused:
+		status = "pause"
+	}
+
+	c.write("volume: %d\nrepeat: %d\nrandom: %d\nsingle: %d\nconsume: 0\n", state.Volume, repeat, random, single)
+	c.write("playlist: %d\nplaylistlength: %d\nstate: %s\n", version, state.QueueCount, status)
+
+	if state.QueuePosition >= 0 && state.QueuePosition < state.QueueCount {
+		c.write("song: %d\nsongid: %d\n", state.QueuePosition, state.QueuePosition)
+	}
+
+	if state.Status != player.StatusStopped {
+		c.write("time: %d:%d\nelapsed: %d.000\nduration: %d.000\n",
+			state.Position, state.Duration, state.Position, state.Duration,
+		)
+	}
+}
+
+// song writes the information of a queue entry.
+func (c *mpdClient) song(entry player.QueueEntry) {
+	c.write("file: https://www.youtube.com/watch?v=%s\n", entry.VideoID)
+	c.write("Title: %s\nArtist: %s\n", mpdValue(entry.Title), mpdValue(entry.Author))
+	c.write("Time: %d\nduration: %d.000\n", entry.Duration, entry.Duration)
+	c.write("Pos: %d\nId: %d\n", entry.Position, entry.Position)
+}
+
+// write writes the formatted text to the client.
+func (c *mpdClient) write(format string, values ...interface{}) {
+	fmt.Fprintf(c.writer, format, values...)
+}
+
+// Error returns the error message.
+func (e mpdError) Error() string {
+	return e.message
+}
+
+// mpdArgs splits the command line into its arguments. Arguments
+// can be quoted, and can contain escaped characters within quotes.
+func mpdArgs(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	var quoted, escaped, started bool
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+
+		case r == '\\' && quoted:
+			escaped = true
+
+		case r == '"':
+			quoted = !quoted
+			started = true
+
+		case (r == ' ' || r == '\t') && !quoted:
+			if started {
+				args = append(args, arg.String())
+				arg.Reset()
+				started = false
+			}
+
+		default:
+			arg.WriteRune(r)
+			started = true
+		}
+	}
+
+	if quoted {
+		return nil, mpdError{mpdErrorArg, "Missing closing '\"'"}
+	}
+	if started {
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
+
+// mpdInt parses an integer argument.
+func mpdInt(arg string) (int, error) {
+	value, err := strconv.Atoi(arg)
+	if err != nil || value < 0 {
+		return -1, mpdError{mpdErrorArg, "Number expected: " + arg}
+	}
+
+	return value, nil
+}
+
+// mpdRange parses a position, or a range of positions in the 'START:END' form,
+// where END is excluded from the range. If END is omitted, -1 is returned as the end.
+func mpdRange(arg string) (int, int, error) {
+	first, last, isRange := strings.Cut(arg, ":")
+
+	start, err := mpdInt(first)
+	if err != nil {
+		return -1, -1, err
+	}
+	if !isRange {
+		return start, start + 1, nil
+	}
+	if last == "" {
+		return start, -1, nil
+	}
+
+	end, err := mpdInt(last)
+	if err != nil {
+		return -1, -1, err
+	}
+	if end < start {
+		return -1, -1, mpdError{mpdErrorArg, "Bad song index: " + arg}
+	}
+
+	return start, end, nil
+}
+
+// mpdResult converts a player error to an MPD protocol error.
+func mpdResult(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return mpdError{mpdErrorArg, err.Error()}
+}
+
+// mpdValue removes newlines from a response value.
+func mpdValue(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
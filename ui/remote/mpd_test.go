@@ -0,0 +1,64 @@
+package remote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMPDArgs(t *testing.T) {
+	tests := []struct {
+		line  string
+		args  []string
+		valid bool
+	}{
+		{"", nil, true},
+		{"status", []string{"status"}, true},
+		{"  play   3 ", []string{"play", "3"}, true},
+		{"setvol\t50", []string{"setvol", "50"}, true},
+		{`add "https://youtu.be/abc def"`, []string{"add", "https://youtu.be/abc def"}, true},
+		{`find "" x`, []string{"find", "", "x"}, true},
+		{`search any "say \"hi\""`, []string{"search", "any", `say "hi"`}, true},
+		{`search any "back\\slash"`, []string{"search", "any", `back\slash`}, true},
+		{`list "unterminated`, nil, false},
+	}
+
+	for _, test := range tests {
+		args, err := mpdArgs(test.line)
+		if (err == nil) != test.valid {
+			t.Errorf("mpdArgs(%q): error %v, valid %v", test.line, err, test.valid)
+			continue
+		}
+		if !reflect.DeepEqual(args, test.args) {
+			t.Errorf("mpdArgs(%q) = %q, want %q", test.line, args, test.args)
+		}
+	}
+}
+
+func TestMPDRange(t *testing.T) {
+	tests := []struct {
+		arg        string
+		start, end int
+		valid      bool
+	}{
+		{"0", 0, 1, true},
+		{"4", 4, 5, true},
+		{"2:5", 2, 5, true},
+		{"3:", 3, -1, true},
+		{"3:3", 3, 3, true},
+		{"5:2", -1, -1, false},
+		{"-1", -1, -1, false},
+		{":4", -1, -1, false},
+		{"a:b", -1, -1, false},
+	}
+
+	for _, test := range tests {
+		start, end, err := mpdRange(test.arg)
+		if (err == nil) != test.valid {
+			t.Errorf("mpdRange(%q): error %v, valid %v", test.arg, err, test.valid)
+			continue
+		}
+		if start != test.start || end != test.end {
+			t.Errorf("mpdRange(%q) = %d, %d, want %d, %d", test.arg, start, end, test.start, test.end)
+		}
+	}
+}
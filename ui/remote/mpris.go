@@ -0,0 +1,568 @@
+//go:build linux
+// +build linux
+
+package remote
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/player"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+// MPRIS describes the MPRIS D-Bus service.
+type MPRIS struct {
+	conn  *dbus.Conn
+	props *prop.Properties
+
+	tracks string
+
+	mutex sync.Mutex
+}
+
+// mprisRoot describes the org.mpris.MediaPlayer2 interface.
+type mprisRoot struct{}
+
+// mprisPlayer describes the org.mpris.MediaPlayer2.Player interface.
+type mprisPlayer struct{}
+
+// mprisTrackList describes the org.mpris.MediaPlayer2.TrackList interface.
+type mprisTrackList struct{}
+
+// The MPRIS bus name, object path and interfaces.
+const (
+	mprisName = "org.mpris.MediaPlayer2.invidtui"
+	mprisPath = "/org/mpris/MediaPlayer2"
+
+	mprisRootInterface      = "org.mpris.MediaPlayer2"
+	mprisPlayerInterface    = "org.mpris.MediaPlayer2.Player"
+	mprisTrackListInterface = "org.mpris.MediaPlayer2.TrackList"
+
+	mprisTrackPrefix = "/org/invidtui/track/"
+	mprisNoTrack     = "/org/mpris/MediaPlayer2/TrackList/NoTrack"
+)
+
+// mprisMethods maps the method names which conflict with
+// standard Go method signatures to their D-Bus method names.
+var mprisMethods = map[string]string{
+	"SeekOffset": "Seek",
+}
+
+var mpris MPRIS
+
+// StartMPRIS starts the MPRIS D-Bus service, if enabled.
+func StartMPRIS() error {
+	if !cmd.IsOptionEnabled("mpris") {
+		return nil
+	}
+
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("MPRIS: Cannot connect to session bus: %w", err)
+	}
+
+	if err := mpris.export(conn); err != nil {
+		conn.Close()
+		return err
+	}
+
+	player.AddStateListener(mpris.update)
+
+	return nil
+}
+
+// export exports the MPRIS interfaces on the connection,
+// and requests the MPRIS bus name.
+func (m *MPRIS) export(conn *dbus.Conn) error {
+	props, err := prop.Export(conn, mprisPath, mprisProperties())
+	if err != nil {
+		return fmt.Errorf("MPRIS: Cannot export properties: %w", err)
+	}
+
+	for iface, object := range map[string]interface{}{
+		mprisRootInterface:      mprisRoot{},
+		mprisPlayerInterface:    mprisPlayer{},
+		mprisTrackListInterface: mprisTrackList{},
+	} {
+		if err := conn.ExportWithMap(object, mprisMethods, mprisPath, iface); err != nil {
+			return fmt.Errorf("MPRIS: Cannot export %s: %w", iface, err)
+		}
+	}
+
+	node := &introspect.Node{
+		Name: mprisPath,
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			prop.IntrospectData,
+			{
+				Name:       mprisRootInterface,
+				Methods:    introspect.Methods(mprisRoot{}),
+				Properties: props.Introspection(mprisRootInterface),
+			},
+			{
+				Name:       mprisPlayerInterface,
+				Methods:    mprisIntrospectMethods(mprisPlayer{}),
+				Properties: props.Introspection(mprisPlayerInterface),
+				Signals: []introspect.Signal{
+					{
+						Name: "Seeked",
+						Args: []introspect.Arg{{Name: "Position", Type: "x"}},
+					},
+				},
+			},
+			{
+				Name:       mprisTrackListInterface,
+				Methods:    introspect.Methods(mprisTrackList{}),
+				Properties: props.Introspection(mprisTrackListInterface),
+				Signals: []introspect.Signal{
+					{
+						Name: "TrackListReplaced",
+						Args: []introspect.Arg{
+							{Name: "Tracks", Type: "ao"},
+							{Name: "CurrentTrack", Type: "o"},
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := conn.Export(introspect.NewIntrospectable(node), mprisPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return fmt.Errorf("MPRIS: Cannot export introspection data: %w", err)
+	}
+
+	name := mprisName
+	reply, err := conn.RequestName(name, dbus.NameFlagDoNotQueue)
+	if err == nil && reply != dbus.RequestNameReplyPrimaryOwner {
+		name += ".instance" + strconv.Itoa(os.Getpid())
+		reply, err = conn.RequestName(name, dbus.NameFlagDoNotQueue)
+	}
+	if err != nil {
+		return fmt.Errorf("MPRIS: Cannot request bus name: %w", err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return fmt.Errorf("MPRIS: Bus name %s is already taken", name)
+	}
+
+	m.mutex.Lock()
+	m.conn = conn
+	m.props = props
+	m.mutex.Unlock()
+
+	return nil
+}
+
+// stopMPRIS stops the MPRIS D-Bus service.
+func stopMPRIS() {
+	mpris.mutex.Lock()
+	defer mpris.mutex.Unlock()
+
+	if mpris.conn == nil {
+		return
+	}
+
+	mpris.conn.Close()
+	mpris.conn = nil
+}
+
+// Raise is unsupported, since the player cannot be brought to the front.
+func (r mprisRoot) Raise() *dbus.Error {
+	return nil
+}
+
+// Quit is unsupported, since the application can only be exited from the UI.
+func (r mprisRoot) Quit() *dbus.Error {
+	return nil
+}
+
+// Next plays the next track.
+func (p mprisPlayer) Next() *dbus.Error {
+	return mprisError(player.Control("next"))
+}
+
+// Previous plays the previous track.
+func (p mprisPlayer) Previous() *dbus.Error {
+	return mprisError(player.Control("previous"))
+}
+
+// Pause pauses playback.
+func (p mprisPlayer) Pause() *dbus.Error {
+	return mprisError(player.Control("pause"))
+}
+
+// PlayPause toggles between playing and pausing.
+func (p mprisPlayer) PlayPause() *dbus.Error {
+	return mprisError(player.Control("toggle"))
+}
+
+// Stop stops playback.
+func (p mprisPlayer) Stop() *dbus.Error {
+	return mprisError(player.Control("stop"))
+}
+
+// Play starts or resumes playback. If the player is stopped,
+// the current track within the queue is played.
+func (p mprisPlayer) Play() *dbus.Error {
+	state := player.GetState()
+	if state.Status != player.StatusStopped || state.QueueCount == 0 {
+		return mprisError(player.Control("play"))
+	}
+
+	position := state.QueuePosition
+	if position < 0 {
+		position = 0
+	}
+
+	return mprisError(player.QueuePlay(position))
+}
+
+// SeekOffset seeks forward or backward by the provided offset in microseconds.
+func (p mprisPlayer) SeekOffset(offset int64) *dbus.Error {
+	state := player.GetState()
+	if state.Status == player.StatusStopped {
+		return nil
+	}
+
+	position := state.Position + offset/1e6
+	if position < 0 {
+		position = 0
+	}
+	if state.Duration > 0 && position > state.Duration {
+		return mprisError(player.Control("next"))
+	}
+
+	return mprisError(player.Control("seek", position))
+}
+
+// SetPosition seeks to the provided position in microseconds,
+// if the track is the currently playing one.
+func (p mprisPlayer) SetPosition(track dbus.ObjectPath, position int64) *dbus.Error {
+	state := player.GetState()
+	if state.Status == player.StatusStopped || track != mprisTrackID(state.QueuePosition) {
+		return nil
+	}
+
+	position /= 1e6
+	if position < 0 || (state.Duration > 0 && position > state.Duration) {
+		return nil
+	}
+
+	return mprisError(player.Control("seek", position))
+}
+
+// OpenUri adds the video or playlist from the provided URL to the queue.
+func (p mprisPlayer) OpenUri(uri string) *dbus.Error {
+	return mprisError(player.QueueAdd(uri, true))
+}
+
+// GetTracksMetadata returns the metadata of the provided tracks.
+func (t mprisTrackList) GetTracksMetadata(tracks []dbus.ObjectPath) ([]map[string]dbus.Variant, *dbus.Error) {
+	var metadata []map[string]dbus.Variant
+
+	entries := player.QueueEntries()
+	for _, track := range tracks {
+		position, ok := mprisTrackPosition(track)
+		if !ok || position >= len(entries) {
+			continue
+		}
+
+		entry := entries[position]
+		metadata = append(metadata, mprisMetadata(
+			position, entry.VideoID, entry.Title, entry.Author, entry.Thumbnail, entry.Duration,
+		))
+	}
+
+	return metadata, nil
+}
+
+// AddTrack adds the video or playlist from the provided URL to the queue.
+// Since the queue only supports appending entries, the position and
+// 'setAsCurrent' parameters are ignored.
+func (t mprisTrackList) AddTrack(uri string, after dbus.ObjectPath, setAsCurrent bool) *dbus.Error {
+	return mprisError(player.QueueAdd(uri, true))
+}
+
+// RemoveTrack removes the track from the queue.
+func (t mprisTrackList) RemoveTrack(track dbus.ObjectPath) *dbus.Error {
+	position, ok := mprisTrackPosition(track)
+	if !ok {
+		return nil
+	}
+
+	return mprisError(player.QueueDelete(position))
+}
+
+// GoTo plays the track.
+func (t mprisTrackList) GoTo(track dbus.ObjectPath) *dbus.Error {
+	position, ok := mprisTrackPosition(track)
+	if !ok {
+		return nil
+	}
+
+	return mprisError(player.QueuePlay(position))
+}
+
+// update updates the MPRIS properties according to the player state.
+func (m *MPRIS) update(previous, current player.State) {
+	m.updateState(previous, current, player.QueueEntries())
+}
+
+// updateState updates the MPRIS properties according to
+// the player state and the entries within the queue.
+func (m *MPRIS) updateState(previous, current player.State, entries []player.QueueEntry) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.conn == nil {
+		return
+	}
+
+	m.props.SetMust(mprisPlayerInterface, "Position", current.Position*1e6)
+
+	if previous.Status != current.Status {
+		m.props.SetMust(mprisPlayerInterface, "PlaybackStatus", mprisStatus(current.Status))
+	}
+	if previous.Repeat != current.Repeat {
+		m.props.SetMust(mprisPlayerInterface, "LoopStatus", mprisLoopStatus(current.Repeat))
+	}
+	if previous.Shuffle != current.Shuffle {
+		m.props.SetMust(mprisPlayerInterface, "Shuffle", current.Shuffle)
+	}
+	if previous.Volume != current.Volume {
+		m.props.SetMust(mprisPlayerInterface, "Volume", float64(current.Volume)/100)
+	}
+
+	if previous.VideoID != current.VideoID || previous.Status == player.StatusStopped ||
+		previous.QueuePosition != current.QueuePosition || previous.Duration != current.Duration {
+		metadata := mprisMetadata(-1, "", "", "", "", 0)
+		if current.Status != player.StatusStopped {
+			metadata = mprisMetadata(
+				current.QueuePosition, current.VideoID, current.Title,
+				current.Author, current.Thumbnail, current.Duration,
+			)
+		}
+
+		m.props.SetMust(mprisPlayerInterface, "Metadata", metadata)
+	} else if current.Status != player.StatusStopped && math.Abs(float64(current.Position-previous.Position)) > 2 {
+		m.conn.Emit(mprisPath, mprisPlayerInterface+".Seeked", current.Position*1e6)
+	}
+
+	m.updateTracks(current, entries)
+}
+
+// updateTracks updates the track list if the queue has changed.
+func (m *MPRIS) updateTracks(current player.State, entries []player.QueueEntry) {
+	var ids []string
+	var tracks []dbus.ObjectPath
+
+	for _, entry := range entries {
+		ids = append(ids, entry.VideoID)
+		tracks = append(tracks, mprisTrackID(entry.Position))
+	}
+
+	list := strings.Join(ids, ",")
+	if list == m.tracks {
+		return
+	}
+
+	m.tracks = list
+	if tracks == nil {
+		tracks = []dbus.ObjectPath{}
+	}
+
+	m.props.SetMust(mprisTrackListInterface, "Tracks", tracks)
+	m.conn.Emit(
+		mprisPath, mprisTrackListInterface+".TrackListReplaced",
+		tracks, mprisTrackID(current.QueuePosition),
+	)
+}
+
+// mprisProperties returns the initial MPRIS properties.
+func mprisProperties() prop.Map {
+	return prop.Map{
+		mprisRootInterface: {
+			"CanQuit":             {Value: false, Emit: prop.EmitConst},
+			"CanRaise":            {Value: false, Emit: prop.EmitConst},
+			"HasTrackList":        {Value: true, Emit: prop.EmitConst},
+			"Identity":            {Value: "invidtui", Emit: prop.EmitConst},
+			"SupportedUriSchemes": {Value: []string{"https"}, Emit: prop.EmitConst},
+			"SupportedMimeTypes":  {Value: []string{}, Emit: prop.EmitConst},
+		},
+		mprisPlayerInterface: {
+			"PlaybackStatus": {Value: mprisStatus(player.StatusStopped), Emit: prop.EmitTrue},
+			"LoopStatus": {
+				Value:    mprisLoopStatus("off"),
+				Writable: true,
+				Emit:     prop.EmitTrue,
+				Callback: setLoopStatus,
+			},
+			"Rate": {Value: 1.0, Emit: prop.EmitConst},
+			"Shuffle": {
+				Value:    false,
+				Writable: true,
+				Emit:     prop.EmitTrue,
+				Callback: setShuffle,
+			},
+			"Metadata": {
+				Value: mprisMetadata(-1, "", "", "", "", 0),
+				Emit:  prop.EmitTrue,
+			},
+			"Volume": {
+				Value:    1.0,
+				Writable: true,
+				Emit:     prop.EmitTrue,
+				Callback: setVolume,
+			},
+			"Position":      {Value: int64(0), Emit: prop.EmitFalse},
+			"MinimumRate":   {Value: 1.0, Emit: prop.EmitConst},
+			"MaximumRate":   {Value: 1.0, Emit: prop.EmitConst},
+			"CanGoNext":     {Value: true, Emit: prop.EmitConst},
+			"CanGoPrevious": {Value: true, Emit: prop.EmitConst},
+			"CanPlay":       {Value: true, Emit: prop.EmitConst},
+			"CanPause":      {Value: true, Emit: prop.EmitConst},
+			"CanSeek":       {Value: true, Emit: prop.EmitConst},
+			"CanControl":    {Value: true, Emit: prop.EmitConst},
+		},
+		mprisTrackListInterface: {
+			"Tracks":        {Value: []dbus.ObjectPath{}, Emit: prop.EmitInvalidates},
+			"CanEditTracks": {Value: true, Emit: prop.EmitConst},
+		},
+	}
+}
+
+// setLoopStatus sets the repeat mode according to the loop status.
+func setLoopStatus(c *prop.Change) *dbus.Error {
+	status, _ := c.Value.(string)
+
+	for i := 0; i < 3; i++ {
+		if mprisLoopStatus(player.GetState().Repeat) == status {
+			return nil
+		}
+
+		if err := player.Control("repeat"); err != nil {
+			return mprisError(err)
+		}
+	}
+
+	return prop.ErrInvalidArg
+}
+
+// setShuffle sets the shuffle mode.
+func setShuffle(c *prop.Change) *dbus.Error {
+	shuffle, _ := c.Value.(bool)
+	if player.GetState().Shuffle == shuffle {
+		return nil
+	}
+
+	return mprisError(player.Control("shuffle"))
+}
+
+// setVolume sets the volume, where 1.0 corresponds to a volume of 100.
+func setVolume(c *prop.Change) *dbus.Error {
+	volume, _ := c.Value.(float64)
+	if volume < 0 {
+		volume = 0
+	}
+
+	return mprisError(player.Control("volume", int64(math.Round(volume*100))))
+}
+
+// mprisMetadata returns the MPRIS metadata for a track. Since the exported
+// metadata is merged with the new metadata instead of being replaced by it,
+// all keys are always set, so that no values of the previous track remain.
+func mprisMetadata(position int, id, title, author, thumbnail string, duration int64) map[string]dbus.Variant {
+	url, artist := "", []string{}
+	if id != "" {
+		url = "https://www.youtube.com/watch?v=" + id
+	}
+	if author != "" {
+		artist = []string{author}
+	}
+
+	return map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(mprisTrackID(position)),
+		"mpris:length":  dbus.MakeVariant(duration * 1e6),
+		"mpris:artUrl":  dbus.MakeVariant(thumbnail),
+		"xesam:title":   dbus.MakeVariant(title),
+		"xesam:artist":  dbus.MakeVariant(artist),
+		"xesam:url":     dbus.MakeVariant(url),
+	}
+}
+
+// mprisTrackID returns the track ID for the queue position.
+func mprisTrackID(position int) dbus.ObjectPath {
+	if position < 0 {
+		return mprisNoTrack
+	}
+
+	return dbus.ObjectPath(mprisTrackPrefix + strconv.Itoa(position))
+}
+
+// mprisTrackPosition returns the queue position for the track ID.
+func mprisTrackPosition(track dbus.ObjectPath) (int, bool) {
+	if !strings.HasPrefix(string(track), mprisTrackPrefix) {
+		return -1, false
+	}
+
+	position, err := strconv.Atoi(strings.TrimPrefix(string(track), mprisTrackPrefix))
+	if err != nil {
+		return -1, false
+	}
+
+	return position, true
+}
+
+// mprisStatus returns the MPRIS playback status for the player status.
+func mprisStatus(status string) string {
+	switch status {
+	case player.StatusPlaying:
+		return "Playing"
+
+	case player.StatusPaused:
+		return "Paused"
+	}
+
+	return "Stopped"
+}
+
+// mprisLoopStatus returns the MPRIS loop status for the repeat mode.
+func mprisLoopStatus(repeat string) string {
+	switch repeat {
+	case "file":
+		return "Track"
+
+	case "playlist":
+		return "Playlist"
+	}
+
+	return "None"
+}
+
+// mprisIntrospectMethods returns the introspection data for the object's methods,
+// with the method names mapped to their D-Bus method names.
+func mprisIntrospectMethods(object interface{}) []introspect.Method {
+	methods := introspect.Methods(object)
+	for i, method := range methods {
+		if name, ok := mprisMethods[method.Name]; ok {
+			methods[i].Name = name
+		}
+	}
+
+	return methods
+}
+
+// mprisError converts the error to a D-Bus error.
+func mprisError(err error) *dbus.Error {
+	if err == nil {
+		return nil
+	}
+
+	return dbus.MakeFailedError(err)
+}
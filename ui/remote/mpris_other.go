@@ -0,0 +1,23 @@
+//go:build !linux
+// +build !linux
+
+package remote
+
+import (
+	"errors"
+
+	"github.com/darkhz/invidtui/cmd"
+)
+
+// StartMPRIS starts the MPRIS D-Bus service, if enabled.
+// It is only supported on Linux.
+func StartMPRIS() error {
+	if !cmd.IsOptionEnabled("mpris") {
+		return nil
+	}
+
+	return errors.New("MPRIS: Not supported on this platform")
+}
+
+// stopMPRIS stops the MPRIS D-Bus service.
+func stopMPRIS() {}
@@ -0,0 +1,285 @@
+//go:build linux
+// +build linux
+
+package remote
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darkhz/invidtui/ui/player"
+	"github.com/godbus/dbus/v5"
+)
+
+const testBusConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// startTestBus starts a private message bus, and returns its address.
+// The test is skipped if dbus-daemon is not installed.
+func startTestBus(t *testing.T) string {
+	path, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon is not installed")
+	}
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "bus.conf")
+
+	if err := os.WriteFile(config, []byte(fmt.Sprintf(testBusConfig, filepath.Join(dir, "bus"))), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	bus := exec.Command(path, "--config-file="+config, "--nofork", "--print-address=1")
+
+	stdout, err := bus.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Start(); err != nil {
+		t.Skipf("Cannot start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		bus.Process.Kill()
+		bus.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("Cannot read the bus address: %v", err)
+	}
+
+	return strings.TrimSpace(address)
+}
+
+// connectTestBus connects to the private message bus.
+func connectTestBus(t *testing.T, address string) *dbus.Conn {
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatalf("Cannot connect to the bus: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return conn
+}
+
+// waitSignal waits for the signal with the provided name to be received.
+func waitSignal(t *testing.T, signals chan *dbus.Signal, name string) *dbus.Signal {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case signal := <-signals:
+			if signal.Name == name {
+				return signal
+			}
+
+		case <-timeout:
+			t.Fatalf("Timed out waiting for signal %s", name)
+		}
+	}
+}
+
+// property returns the value of the MPRIS property.
+func property(t *testing.T, object dbus.BusObject, name string) interface{} {
+	t.Helper()
+
+	value, err := object.GetProperty(name)
+	if err != nil {
+		t.Fatalf("GetProperty(%s): %v", name, err)
+	}
+
+	return value.Value()
+}
+
+func TestMPRIS(t *testing.T) {
+	var m MPRIS
+
+	address := startTestBus(t)
+	if err := m.export(connectTestBus(t, address)); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	client := connectTestBus(t, address)
+	object := client.Object(mprisName, mprisPath)
+
+	var data string
+	if err := object.Call("org.freedesktop.DBus.Introspectable.Introspect", 0).Store(&data); err != nil {
+		t.Fatalf("Introspect: %v", err)
+	}
+	for _, want := range []string{
+		`<interface name="` + mprisRootInterface + `">`,
+		`<interface name="` + mprisPlayerInterface + `">`,
+		`<interface name="` + mprisTrackListInterface + `">`,
+		`<method name="Seek">`,
+		`<signal name="Seeked">`,
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("Introspection data does not contain %s", want)
+		}
+	}
+	if strings.Contains(data, "SeekOffset") {
+		t.Error("Introspection data contains the unmapped SeekOffset method")
+	}
+
+	for name, want := range map[string]interface{}{
+		mprisRootInterface + ".Identity":         "invidtui",
+		mprisRootInterface + ".HasTrackList":     true,
+		mprisPlayerInterface + ".PlaybackStatus": "Stopped",
+		mprisPlayerInterface + ".CanSeek":        true,
+	} {
+		if value := property(t, object, name); value != want {
+			t.Errorf("%s = %v, want %v", name, value, want)
+		}
+	}
+
+	if err := client.AddMatchSignal(dbus.WithMatchObjectPath(mprisPath)); err != nil {
+		t.Fatalf("AddMatchSignal: %v", err)
+	}
+
+	signals := make(chan *dbus.Signal, 32)
+	client.Signal(signals)
+
+	entries := []player.QueueEntry{
+		{Position: 0, VideoID: "abc"},
+		{Position: 1, VideoID: "def"},
+	}
+	stopped := player.State{Status: player.StatusStopped, Repeat: "off", Volume: 100, QueuePosition: -1}
+	playing := player.State{
+		Status:        player.StatusPlaying,
+		VideoID:       "abc",
+		Title:         "Track",
+		Author:        "Artist",
+		Thumbnail:     "https://i.ytimg.com/vi/abc/hqdefault.jpg",
+		Position:      10,
+		Duration:      200,
+		Volume:        50,
+		Repeat:        "file",
+		Shuffle:       true,
+		QueuePosition: 0,
+		QueueCount:    2,
+	}
+
+	m.updateState(stopped, playing, entries)
+
+	replaced := waitSignal(t, signals, mprisTrackListInterface+".TrackListReplaced")
+	tracks := []dbus.ObjectPath{mprisTrackPrefix + "0", mprisTrackPrefix + "1"}
+	if !reflect.DeepEqual(replaced.Body, []interface{}{tracks, tracks[0]}) {
+		t.Errorf("TrackListReplaced = %v, want %v", replaced.Body, []interface{}{tracks, tracks[0]})
+	}
+
+	for name, want := range map[string]interface{}{
+		mprisPlayerInterface + ".PlaybackStatus": "Playing",
+		mprisPlayerInterface + ".LoopStatus":     "Track",
+		mprisPlayerInterface + ".Shuffle":        true,
+		mprisPlayerInterface + ".Volume":         0.5,
+		mprisPlayerInterface + ".Position":       int64(10e6),
+	} {
+		if value := property(t, object, name); value != want {
+			t.Errorf("%s = %v, want %v", name, value, want)
+		}
+	}
+	if value := property(t, object, mprisTrackListInterface+".Tracks"); !reflect.DeepEqual(value, tracks) {
+		t.Errorf("Tracks = %v, want %v", value, tracks)
+	}
+
+	metadata, _ := property(t, object, mprisPlayerInterface+".Metadata").(map[string]dbus.Variant)
+	for key, want := range map[string]interface{}{
+		"mpris:trackid": tracks[0],
+		"mpris:length":  int64(200e6),
+		"mpris:artUrl":  playing.Thumbnail,
+		"xesam:title":   "Track",
+		"xesam:artist":  []string{"Artist"},
+		"xesam:url":     "https://www.youtube.com/watch?v=abc",
+	} {
+		if value := metadata[key].Value(); !reflect.DeepEqual(value, want) {
+			t.Errorf("Metadata[%s] = %v, want %v", key, value, want)
+		}
+	}
+
+	seeked := playing
+	seeked.Position = 100
+
+	m.updateState(playing, seeked, entries)
+
+	signal := waitSignal(t, signals, mprisPlayerInterface+".Seeked")
+	if !reflect.DeepEqual(signal.Body, []interface{}{int64(100e6)}) {
+		t.Errorf("Seeked = %v, want %d", signal.Body, int64(100e6))
+	}
+
+	m.updateState(seeked, stopped, entries)
+
+	if value := property(t, object, mprisPlayerInterface+".PlaybackStatus"); value != "Stopped" {
+		t.Errorf("PlaybackStatus = %v, want Stopped", value)
+	}
+	metadata, _ = property(t, object, mprisPlayerInterface+".Metadata").(map[string]dbus.Variant)
+	for key, want := range map[string]interface{}{
+		"mpris:trackid": dbus.ObjectPath(mprisNoTrack),
+		"mpris:length":  int64(0),
+		"mpris:artUrl":  "",
+		"xesam:title":   "",
+		"xesam:artist":  []string{},
+		"xesam:url":     "",
+	} {
+		if value := metadata[key].Value(); !reflect.DeepEqual(value, want) {
+			t.Errorf("Metadata[%s] = %v, want %v", key, value, want)
+		}
+	}
+
+	for _, method := range []string{"GoTo", "RemoveTrack"} {
+		call := object.Call(mprisTrackListInterface+"."+method, 0, dbus.ObjectPath("/org/invidtui/other/0"))
+		if call.Err != nil {
+			t.Errorf("%s: %v", method, call.Err)
+		}
+	}
+}
+
+func TestMPRISBusName(t *testing.T) {
+	var first, second, third MPRIS
+
+	address := startTestBus(t)
+	conn := connectTestBus(t, address)
+
+	if err := first.export(connectTestBus(t, address)); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	instance := connectTestBus(t, address)
+	if err := second.export(instance); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	var owner string
+	name := mprisName + ".instance" + strconv.Itoa(os.Getpid())
+	if err := conn.BusObject().Call("org.freedesktop.DBus.GetNameOwner", 0, name).Store(&owner); err != nil {
+		t.Fatalf("GetNameOwner(%s): %v", name, err)
+	}
+	if owner != instance.Names()[0] {
+		t.Errorf("GetNameOwner(%s) = %s, want %s", name, owner, instance.Names()[0])
+	}
+
+	if err := third.export(connectTestBus(t, address)); err == nil {
+		t.Error("export: expected an error when both bus names are taken")
+	}
+}
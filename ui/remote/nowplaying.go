@@ -0,0 +1,222 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/platform"
+	"github.com/darkhz/invidtui/ui/player"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// NowPlaying describes the now-playing exporter.
+type NowPlaying struct {
+	file     string
+	template *template.Template
+
+	path     string
+	listener net.Listener
+	clients  map[net.Conn]struct{}
+
+	output []byte
+
+	mutex sync.Mutex
+}
+
+// NowPlayingSocketName is the name of the now-playing socket within the config directory.
+const NowPlayingSocketName = "nowplaying.sock"
+
+var nowPlaying NowPlaying
+
+// StartNowPlaying starts exporting the now-playing status to the configured file
+// and/or socket, whenever the player state changes. The status is exported as JSON,
+// or according to the template provided in the 'now-playing-format' option.
+func StartNowPlaying() error {
+	file := cmd.GetOptionValue("now-playing-file")
+	socket := cmd.IsOptionEnabled("now-playing-socket")
+	if file == "" && !socket {
+		return nil
+	}
+
+	var tmpl *template.Template
+
+	if format := cmd.GetOptionValue("now-playing-format"); format != "" && format != "json" {
+		t, err := template.New("nowplaying").
+			Funcs(template.FuncMap{"duration": utils.FormatDuration}).
+			Parse(format)
+		if err != nil {
+			return fmt.Errorf("NowPlaying: Invalid format: %w", err)
+		}
+
+		tmpl = t
+	}
+
+	nowPlaying.mutex.Lock()
+	nowPlaying.file = file
+	nowPlaying.template = tmpl
+	nowPlaying.clients = make(map[net.Conn]struct{})
+	nowPlaying.mutex.Unlock()
+
+	if socket {
+		if err := nowPlaying.listen(); err != nil {
+			return err
+		}
+	}
+
+	nowPlaying.update(player.State{}, player.State{Status: player.StatusStopped})
+	player.AddStateListener(nowPlaying.update)
+
+	return nil
+}
+
+// stopNowPlaying exports the stopped status, and stops the now-playing socket.
+func stopNowPlaying() {
+	nowPlaying.mutex.Lock()
+	started := nowPlaying.clients != nil
+	nowPlaying.mutex.Unlock()
+
+	if !started {
+		return
+	}
+
+	nowPlaying.update(player.State{}, player.State{Status: player.StatusStopped})
+
+	nowPlaying.mutex.Lock()
+	defer nowPlaying.mutex.Unlock()
+
+	if nowPlaying.listener == nil {
+		return
+	}
+
+	nowPlaying.listener.Close()
+	os.Remove(nowPlaying.path)
+
+	for conn := range nowPlaying.clients {
+		conn.Close()
+	}
+
+	nowPlaying.listener = nil
+}
+
+// listen starts listening on the now-playing socket. Each client receives
+// the current status on connecting, and a new line on every change.
+func (n *NowPlaying) listen() error {
+	dir, err := cmd.GetConfigDir("")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, NowPlayingSocketName)
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("NowPlaying: Cannot remove stale socket: %w", err)
+	}
+
+	listener, err := platform.ListenSocket(path)
+	if err != nil {
+		return fmt.Errorf("NowPlaying: Cannot listen on socket: %w", err)
+	}
+
+	n.mutex.Lock()
+	n.path = path
+	n.listener = listener
+	n.mutex.Unlock()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			n.mutex.Lock()
+			n.clients[conn] = struct{}{}
+			output := n.output
+			n.mutex.Unlock()
+
+			if err := nowPlayingWrite(conn, output); err != nil {
+				n.removeClient(conn)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// update formats the current state, and exports it to the file and the socket.
+// The clients are written to without holding the lock, so that a slow
+// client does not block the other operations on the exporter.
+func (n *NowPlaying) update(previous, current player.State) {
+	var output bytes.Buffer
+
+	n.mutex.Lock()
+
+	if n.template != nil {
+		if err := n.template.Execute(&output, current); err != nil {
+			output.Reset()
+			output.WriteString(err.Error())
+		}
+		if !bytes.HasSuffix(output.Bytes(), []byte("\n")) {
+			output.WriteByte('\n')
+		}
+	} else if err := json.NewEncoder(&output).Encode(current); err != nil {
+		n.mutex.Unlock()
+		return
+	}
+
+	if bytes.Equal(output.Bytes(), n.output) {
+		n.mutex.Unlock()
+		return
+	}
+
+	n.output = output.Bytes()
+
+	if n.file != "" {
+		temp := n.file + ".tmp"
+		if err := os.WriteFile(temp, n.output, 0644); err == nil {
+			os.Rename(temp, n.file)
+		}
+	}
+
+	clients := make([]net.Conn, 0, len(n.clients))
+	for conn := range n.clients {
+		clients = append(clients, conn)
+	}
+
+	data := n.output
+	n.mutex.Unlock()
+
+	for _, conn := range clients {
+		if err := nowPlayingWrite(conn, data); err != nil {
+			n.removeClient(conn)
+		}
+	}
+}
+
+// removeClient closes the connection to the client, and removes it.
+func (n *NowPlaying) removeClient(conn net.Conn) {
+	conn.Close()
+
+	n.mutex.Lock()
+	delete(n.clients, conn)
+	n.mutex.Unlock()
+}
+
+// nowPlayingWrite writes the output to the client. Clients which do not
+// read the output within a second are considered to be disconnected.
+func nowPlayingWrite(conn net.Conn, output []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(time.Second))
+
+	_, err := conn.Write(output)
+
+	return err
+}
@@ -0,0 +1,283 @@
+package remote
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	inv "github.com/darkhz/invidtui/invidious"
+	"github.com/darkhz/invidtui/platform"
+	"github.com/darkhz/invidtui/ui/player"
+	"github.com/darkhz/invidtui/ui/view"
+)
+
+// Server describes the remote-control server.
+type Server struct {
+	path     string
+	listener net.Listener
+
+	mutex sync.Mutex
+}
+
+// Request describes a JSON-RPC request.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response describes a JSON-RPC response.
+type Response struct {
+	JSONRPC string         `json:"jsonrpc"`
+	ID      interface{}    `json:"id"`
+	Result  interface{}    `json:"result,omitempty"`
+	Error   *ResponseError `json:"error,omitempty"`
+}
+
+// ResponseError describes a JSON-RPC error.
+type ResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Params describes the parameters for all the remote-control methods.
+type Params struct {
+	URL      string `json:"url"`
+	Audio    bool   `json:"audio"`
+	Position int    `json:"position"`
+	From     int    `json:"from"`
+	To       int    `json:"to"`
+	Value    *int64 `json:"value"`
+	Query    string `json:"query"`
+	Type     string `json:"type"`
+	Page     int    `json:"page"`
+	Show     bool   `json:"show"`
+}
+
+// The JSON-RPC error codes.
+const (
+	ErrorParse          = -32700
+	ErrorInvalidRequest = -32600
+	ErrorMethodNotFound = -32601
+	ErrorInvalidParams  = -32602
+	ErrorInternal       = -32603
+)
+
+// SocketName is the name of the remote-control socket within the config directory.
+const SocketName = "control.sock"
+
+var server Server
+
+// Start starts the remote-control server, if enabled.
+func Start() error {
+	if !cmd.IsOptionEnabled("remote-control") {
+		return nil
+	}
+
+	dir, err := cmd.GetConfigDir("")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, SocketName)
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Remote: Cannot remove stale socket: %w", err)
+	}
+
+	listener, err := platform.ListenSocket(path)
+	if err != nil {
+		return fmt.Errorf("Remote: Cannot listen on socket: %w", err)
+	}
+
+	server.mutex.Lock()
+	server.path = path
+	server.listener = listener
+	server.mutex.Unlock()
+
+	go server.accept()
+
+	return nil
+}
+
+// Stop stops the remote-control server, the MPRIS and MPD services,
+// the now-playing exporter and the daemon's attach server.
+func Stop() {
+	stopMPRIS()
+	stopMPD()
+	stopNowPlaying()
+	stopDaemon()
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.listener == nil {
+		return
+	}
+
+	server.listener.Close()
+	os.Remove(server.path)
+
+	server.listener = nil
+}
+
+// accept accepts and handles connections to the server.
+func (s *Server) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+// handle reads requests from the connection, with one request per line,
+// and writes the responses back to it.
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	encoder := json.NewEncoder(conn)
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), 1024*1024)
+
+	for scanner.Scan() {
+		var request Request
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		response := Response{JSONRPC: "2.0"}
+
+		if err := json.Unmarshal(line, &request); err != nil {
+			response.Error = &ResponseError{ErrorParse, "Parse error"}
+		} else {
+			response.ID = request.ID
+			response.Result, response.Error = dispatch(request)
+
+			// Requests without an ID are notifications,
+			// which must not be replied to.
+			if request.ID == nil {
+				continue
+			}
+		}
+
+		if err := encoder.Encode(response); err != nil {
+			return
+		}
+	}
+}
+
+// dispatch runs the method of the request.
+//
+//gocyclo:ignore
+func dispatch(request Request) (interface{}, *ResponseError) {
+	var err error
+	var params Params
+
+	if request.Method == "" {
+		return nil, &ResponseError{ErrorInvalidRequest, "Invalid request"}
+	}
+
+	if len(request.Params) > 0 {
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, &ResponseError{ErrorInvalidParams, err.Error()}
+		}
+	}
+
+	switch request.Method {
+	case "queue.list":
+		return player.QueueEntries(), nil
+
+	case "queue.add":
+		if params.URL == "" {
+			return nil, &ResponseError{ErrorInvalidParams, "No URL provided"}
+		}
+
+		err = player.QueueAdd(params.URL, params.Audio)
+
+	case "queue.move":
+		err = player.QueueMove(params.From, params.To)
+
+	case "queue.delete":
+		err = player.QueueDelete(params.Position)
+
+	case "queue.play":
+		err = player.QueuePlay(params.Position)
+
+	case "player.state":
+		return player.GetState(), nil
+
+	case "player.play", "player.pause", "player.toggle", "player.stop",
+		"player.next", "player.previous", "player.mute",
+		"player.repeat", "player.shuffle":
+		err = player.Control(request.Method[len("player."):])
+
+	case "player.seek", "player.volume":
+		if params.Value == nil {
+			return nil, &ResponseError{ErrorInvalidParams, "No value provided"}
+		}
+
+		err = player.Control(request.Method[len("player."):], *params.Value)
+
+	case "search":
+		return search(params)
+
+	default:
+		return nil, &ResponseError{ErrorMethodNotFound, "Method not found"}
+	}
+
+	if err != nil {
+		return nil, &ResponseError{ErrorInternal, err.Error()}
+	}
+
+	return "ok", nil
+}
+
+// search returns the search results for the query. If 'show' is set,
+// the search results are displayed within the search view as well.
+func search(params Params) (interface{}, *ResponseError) {
+	if params.Query == "" {
+		return nil, &ResponseError{ErrorInvalidParams, "No query provided"}
+	}
+
+	stype := params.Type
+	if stype == "" {
+		stype = "video"
+	}
+	if stype != "video" && stype != "playlist" && stype != "channel" {
+		return nil, &ResponseError{ErrorInvalidParams, "Invalid search type " + stype}
+	}
+
+	page := params.Page
+	if page > 0 {
+		page--
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	results, _, err := inv.SearchContext(ctx, stype, params.Query, nil, page)
+	if err != nil {
+		return nil, &ResponseError{ErrorInternal, err.Error()}
+	}
+
+	if params.Show {
+		go view.Search.StartType(stype, params.Query)
+	}
+
+	return results, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -19,6 +20,7 @@ import (
 
 // ThemeConfig describes the theme configuration settings.
 type ThemeConfig struct {
+	file     string
 	settings map[ThemeContext]map[ThemeItem]ThemeSetting
 
 	sync.Mutex
@@ -28,6 +30,7 @@ type ThemeConfig struct {
 type ThemeSetting struct {
 	Style tcell.Style
 	Tag   string
+	Value string
 }
 
 var config ThemeConfig
@@ -41,7 +44,7 @@ func GetConfigHandler() *ThemeConfig {
 func (c *ThemeConfig) Parse(k *koanf.Koanf, dir string) error {
 	themeFile := k.String("theme")
 	if themeFile == "" {
-		return parseConfig(nil)
+		return parseConfig(nil, "")
 	}
 
 	if filepath.Ext(themeFile) == "" {
@@ -83,7 +86,75 @@ func ParseFile(themePath string) error {
 		return err
 	}
 
-	return parseConfig(conf)
+	return parseConfig(conf, themePath)
+}
+
+// CurrentFile returns the path to the currently applied theme file.
+// If the default theme is applied, an empty string is returned.
+func CurrentFile() string {
+	config.Lock()
+	defer config.Unlock()
+
+	return config.file
+}
+
+// ThemeValues returns the parameters of each item within
+// each context of the currently applied theme.
+func ThemeValues() map[ThemeContext]map[ThemeItem]string {
+	config.Lock()
+	defer config.Unlock()
+
+	values := make(map[ThemeContext]map[ThemeItem]string)
+	for context, settings := range config.settings {
+		values[context] = make(map[ThemeItem]string)
+		for item, setting := range settings {
+			values[context][item] = setting.Value
+		}
+	}
+
+	return values
+}
+
+// SetThemeValue sets the parameter for the item within the context, and
+// applies it to the current theme. If the parameter is empty, the item
+// is removed from the context.
+func SetThemeValue(context ThemeContext, item ThemeItem, value string) error {
+	var setting ThemeSetting
+
+	if _, ok := ThemeScopes[context][item]; !ok {
+		return fmt.Errorf("Theme: Item '%s' is not in scope for context '%s'", item, context)
+	}
+
+	if value != "" {
+		style, tag, err := parseThemeSetting(value)
+		if err != nil {
+			return fmt.Errorf("Theme: Invalid theme directive for '%s -> %s' (%s)", context, item, err.Error())
+		}
+
+		setting = ThemeSetting{
+			Style: style,
+			Tag:   tag,
+			Value: value,
+		}
+	}
+
+	config.Lock()
+	defer config.Unlock()
+
+	if config.settings == nil {
+		config.settings = make(map[ThemeContext]map[ThemeItem]ThemeSetting)
+	}
+	if config.settings[context] == nil {
+		config.settings[context] = make(map[ThemeItem]ThemeSetting)
+	}
+
+	if value == "" {
+		delete(config.settings[context], item)
+	} else {
+		config.settings[context][item] = setting
+	}
+
+	return nil
 }
 
 // GetThemeSetting returns a style and tag according to the ThemeProperty.
@@ -118,7 +189,7 @@ Setting:
 }
 
 // parseConfig parses the current configuration or the default.
-func parseConfig(k *koanf.Koanf) error {
+func parseConfig(k *koanf.Koanf, themePath string) error {
 	if k == nil {
 		k = koanf.New(".")
 		if err := k.Load(rawbytes.Provider([]byte(DefaultThemeConfig)), hjson.Parser()); err != nil {
@@ -166,6 +237,7 @@ func parseConfig(k *koanf.Koanf) error {
 		settings[context][item] = ThemeSetting{
 			Style: style,
 			Tag:   tag,
+			Value: setting,
 		}
 	}
 
@@ -175,12 +247,28 @@ func parseConfig(k *koanf.Koanf) error {
 	}
 
 	config.Lock()
+	config.file = themePath
 	config.settings = settings
 	config.Unlock()
 
 	return nil
 }
 
+// paletteIndex returns the index of a color within the 256-color
+// palette, if the color is named in the 'color<index>' format.
+func paletteIndex(name string) (int, bool) {
+	if !strings.HasPrefix(name, "color") {
+		return 0, false
+	}
+
+	index, err := strconv.Atoi(strings.TrimPrefix(name, "color"))
+	if err != nil || index < 0 || index > 255 {
+		return 0, false
+	}
+
+	return index, true
+}
+
 // parseThemeSetting parses a theme setting and returns a style and a tag.
 func parseThemeSetting(setting string) (tcell.Style, string, error) {
 	var style tcell.Style
@@ -238,6 +326,10 @@ func parseThemeSetting(setting string) (tcell.Style, string, error) {
 
 			default:
 				color = tcell.GetColor(name)
+				if index, ok := paletteIndex(name); ok {
+					color = tcell.PaletteColor(index)
+					name = fmt.Sprintf("#%06x", color.Hex())
+				}
 			}
 			if color == 0 && name != "default" {
 				return tcell.Style{}, "", fmt.Errorf("Invalid color '%s'", name)
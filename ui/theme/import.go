@@ -0,0 +1,387 @@
+package theme
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/invidtui/cmd"
+)
+
+// schemePalette stores the colors read from a color scheme.
+type schemePalette struct {
+	foreground, background string
+
+	ansi   [16]string
+	base16 [16]string
+}
+
+// schemeRoles describes the roles which the colors of a scheme are assigned to.
+type schemeRoles struct {
+	fg, bg, surface, muted                                string
+	red, green, yellow, blue, magenta, cyan, pink, orange string
+}
+
+// schemeColorNames matches the alacritty color names to their ANSI color index.
+var schemeColorNames = map[string]int{
+	"black":   0,
+	"red":     1,
+	"green":   2,
+	"yellow":  3,
+	"blue":    4,
+	"magenta": 5,
+	"cyan":    6,
+	"white":   7,
+}
+
+// ImportScheme converts a base16 YAML scheme, or an alacritty, kitty or
+// Xresources color file into a theme, and saves it within the themes directory.
+// If a theme with the same name exists, it is only replaced if overwrite is set,
+// otherwise an error wrapping fs.ErrExist is returned.
+// The path to the saved theme file is returned.
+func ImportScheme(schemePath, name string, overwrite bool) (string, error) {
+	palette, err := parseScheme(schemePath)
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(schemePath), filepath.Ext(schemePath))
+	}
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("Theme: Invalid theme name %q", name)
+	}
+
+	themePath, err := cmd.GetPath(filepath.Join("themes", name+".theme"), struct{}{})
+	if err == nil && !overwrite {
+		return themePath, fmt.Errorf("Theme: %s already exists: %w", themePath, fs.ErrExist)
+	}
+	if themePath == "" {
+		return "", err
+	}
+
+	data := FormatTheme(name, schemeValues(palette.roles()))
+	if err := os.WriteFile(themePath, []byte(data), 0664); err != nil {
+		return "", fmt.Errorf("Theme: Cannot save theme to %s: %w", themePath, err)
+	}
+
+	return themePath, nil
+}
+
+// parseScheme parses the colors from the color scheme file.
+//
+//gocyclo:ignore
+func parseScheme(schemePath string) (schemePalette, error) {
+	var palette schemePalette
+	var section string
+	var found bool
+
+	file, err := os.Open(schemePath)
+	if err != nil {
+		return palette, fmt.Errorf("Theme: Cannot open color scheme: %w", err)
+	}
+	defer file.Close()
+
+	defines := make(map[string]string)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "", strings.HasPrefix(line, "!"), strings.HasPrefix(line, "//"):
+			continue
+
+		case strings.HasPrefix(line, "#define"):
+			if fields := strings.Fields(line); len(fields) >= 3 {
+				defines[fields[1]] = fields[2]
+			}
+
+			continue
+
+		case strings.HasPrefix(line, "#"):
+			continue
+
+		case strings.HasPrefix(line, "["):
+			section = strings.TrimPrefix(strings.Trim(line, "[]"), "colors.")
+			continue
+		}
+
+		key, value := splitSchemeLine(line)
+		if value == "" {
+			section = key
+			continue
+		}
+		if define, ok := defines[value]; ok {
+			value = define
+		}
+
+		color, ok := schemeColor(value)
+		if !ok {
+			continue
+		}
+
+		if i := strings.LastIndexAny(key, ".*"); i >= 0 {
+			key = key[i+1:]
+		}
+		key = strings.ToLower(key)
+
+		switch {
+		case key == "foreground" && (section == "" || section == "primary"):
+			palette.foreground = color
+
+		case key == "background" && (section == "" || section == "primary"):
+			palette.background = color
+
+		case strings.HasPrefix(key, "base") && len(key) == 6:
+			// Base24 schemes define additional colors from 'base10' to 'base17',
+			// which have no roles assigned to them, and are skipped.
+			index, err := strconv.ParseInt(key[4:], 16, 0)
+			if err != nil || index < 0 || index > 15 {
+				continue
+			}
+
+			palette.base16[index] = color
+
+		case strings.HasPrefix(key, "color"):
+			index, err := strconv.Atoi(key[5:])
+			if err != nil || index < 0 || index > 15 {
+				continue
+			}
+
+			palette.ansi[index] = color
+
+		default:
+			index, ok := schemeColorNames[key]
+			if !ok || (section != "normal" && section != "bright") {
+				continue
+			}
+			if section == "bright" {
+				index += 8
+			}
+
+			palette.ansi[index] = color
+		}
+
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return palette, fmt.Errorf("Theme: Cannot read color scheme: %w", err)
+	}
+
+	if !found {
+		return palette, fmt.Errorf("Theme: No colors found in %s", filepath.Base(schemePath))
+	}
+
+	return palette, nil
+}
+
+// splitSchemeLine returns the key and the value from a line of the color scheme.
+func splitSchemeLine(line string) (string, string) {
+	index := strings.IndexAny(line, ":= \t")
+	if index < 0 {
+		return strings.Trim(line, "\"'"), ""
+	}
+
+	key := strings.Trim(strings.TrimSpace(line[:index]), "\"'")
+	value := strings.TrimLeft(line[index:], ":= \t")
+
+	if fields := strings.Fields(value); len(fields) > 0 {
+		value = strings.Trim(fields[0], "\"',")
+	}
+
+	return key, value
+}
+
+// schemeColor returns the color in the '#rrggbb' format.
+func schemeColor(value string) (string, bool) {
+	value = strings.ToLower(value)
+	for _, prefix := range []string{"#", "0x"} {
+		value = strings.TrimPrefix(value, prefix)
+	}
+
+	if len(value) != 6 {
+		return "", false
+	}
+	if _, err := strconv.ParseUint(value, 16, 32); err != nil {
+		return "", false
+	}
+
+	return "#" + value, true
+}
+
+// roles assigns the colors of the palette to their roles.
+func (p schemePalette) roles() schemeRoles {
+	var roles schemeRoles
+
+	if p.base16[0] != "" {
+		b := p.base16
+
+		roles = schemeRoles{
+			fg: b[5], bg: b[0], surface: b[2], muted: b[3],
+			red: b[8], orange: b[9], yellow: b[10], green: b[11],
+			cyan: b[12], blue: b[13], magenta: b[14], pink: b[15],
+		}
+	} else {
+		a := p.ansi
+		for i := 0; i < 8; i++ {
+			if a[i] == "" {
+				a[i] = a[i+8]
+			}
+			if a[i+8] == "" {
+				a[i+8] = a[i]
+			}
+		}
+
+		roles = schemeRoles{
+			fg: p.foreground, bg: p.background, surface: a[8], muted: a[8],
+			red: a[1], green: a[2], yellow: a[3], blue: a[4],
+			magenta: a[5], cyan: a[6], pink: a[13], orange: a[11],
+		}
+	}
+
+	if roles.fg == "" {
+		roles.fg = p.ansi[7]
+	}
+	if roles.bg == "" {
+		roles.bg = p.ansi[0]
+	}
+
+	for _, color := range []*string{&roles.fg, &roles.bg} {
+		if *color == "" {
+			*color = "default"
+		}
+	}
+
+	if roles.surface == "" {
+		roles.surface = roles.bg
+	}
+
+	for _, color := range []*string{
+		&roles.muted,
+		&roles.red, &roles.green, &roles.yellow, &roles.blue,
+		&roles.magenta, &roles.cyan, &roles.pink, &roles.orange,
+	} {
+		if *color == "" {
+			*color = roles.fg
+		}
+	}
+
+	return roles
+}
+
+// schemeValues returns the theme parameters for all theme items,
+// according to the colors assigned to each role.
+//
+//gocyclo:ignore
+func schemeValues(r schemeRoles) map[ThemeContext]map[ThemeItem]string {
+	bold := func(fg string) string {
+		return "attr:bold; fg:" + fg
+	}
+	tag := func(bg string) string {
+		return "attr:bold; bg:" + bg + "; fg:" + r.bg
+	}
+
+	items := map[ThemeItem]string{
+		ThemeBackground:      "bg:" + r.bg,
+		ThemePopupBackground: "bg:" + r.bg,
+		ThemeBorder:          bold(r.muted),
+		ThemePopupBorder:     bold(r.muted),
+
+		ThemeText:        bold(r.fg),
+		ThemeTitle:       "attr:bold,underline; fg:" + r.fg,
+		ThemeTabs:        bold(r.cyan),
+		ThemeName:        bold(r.fg),
+		ThemeDescription: bold(r.fg),
+		ThemeKeybinding:  bold(r.yellow),
+		ThemeComment:     bold(r.fg),
+
+		ThemeInputLabel:  bold(r.fg),
+		ThemeInputField:  "bg:" + r.surface + "; fg:" + r.fg,
+		ThemeListLabel:   bold(r.fg),
+		ThemeListField:   "bg:" + r.surface + "; fg:" + r.fg,
+		ThemeListOptions: "attr:bold; bg:" + r.surface + "; fg:" + r.fg,
+		ThemeFormLabel:   bold(r.fg),
+		ThemeFormField:   "attr:bold; bg:" + r.surface + "; fg:" + r.fg,
+		ThemeFormOptions: "attr:bold; bg:" + r.surface + "; fg:" + r.fg,
+		ThemeFormButton:  tag(r.blue),
+
+		ThemeSelector:           tag(r.blue),
+		ThemeNormalModeSelector: bold(r.fg),
+		ThemeMoveModeSelector:   bold(r.cyan),
+
+		ThemeInfoMessage:  bold(r.fg),
+		ThemeErrorMessage: bold(r.red),
+		ThemeProgressBar:  bold(r.blue),
+		ThemeProgressText: bold(r.fg),
+
+		ThemeTagStatusBar: "bg:" + r.yellow + "; fg:" + r.bg,
+		ThemeTagFetching:  tag(r.yellow),
+		ThemeTagLoading:   tag(r.yellow),
+		ThemeTagAdding:    tag(r.yellow),
+		ThemeTagStopped:   tag(r.red),
+		ThemeTagError:     tag(r.red),
+		ThemeTagPlaying:   tag(r.green),
+		ThemeTagChanged:   bold(r.fg),
+
+		ThemeDirectory: bold(r.blue),
+		ThemeFile:      bold(r.fg),
+		ThemePath:      "attr:bold,underline; fg:" + r.fg,
+
+		ThemeVideo:          bold(r.blue),
+		ThemePlaylist:       bold(r.blue),
+		ThemeChannel:        bold(r.blue),
+		ThemeAuthor:         bold(r.magenta),
+		ThemeAuthorOwner:    bold(r.pink),
+		ThemeAuthorVerified: bold(r.cyan),
+		ThemeTotalVideos:    bold(r.pink),
+
+		ThemeShuffle:       bold(r.fg),
+		ThemeLoop:          bold(r.fg),
+		ThemeVolume:        bold(r.fg),
+		ThemeDuration:      bold(r.fg),
+		ThemeTotalDuration: bold(r.pink),
+		ThemePause:         bold(r.fg),
+		ThemePlay:          bold(r.fg),
+		ThemeBuffer:        bold(r.fg),
+		ThemeMute:          bold(r.fg),
+		ThemeStop:          bold(r.fg),
+
+		ThemeViews:       bold(r.pink),
+		ThemeLikes:       bold(r.red),
+		ThemeSubscribers: bold(r.magenta),
+		ThemePublished:   bold(r.cyan),
+
+		ThemeInstanceURI:  bold(r.blue),
+		ThemeInvidiousURI: bold(r.blue),
+		ThemeYoutubeURI:   bold(r.red),
+
+		ThemeMediaInfo:       bold(r.red),
+		ThemeMediaSize:       bold(r.blue),
+		ThemeMediaType:       bold(r.pink),
+		ThemeVideoResolution: bold(r.green),
+		ThemeVideoFPS:        bold(r.yellow),
+		ThemeAudioSampleRate: bold(r.orange),
+		ThemeAudioChannels:   bold(r.muted),
+	}
+
+	values := make(map[ThemeContext]map[ThemeItem]string)
+	for context, scopes := range ThemeScopes {
+		values[context] = make(map[ThemeItem]string)
+
+		for item := range scopes {
+			value, ok := items[item]
+			if !ok {
+				value = bold(r.fg)
+			}
+
+			values[context][item] = value
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,260 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testBase16 = `scheme: "Tomorrow Night"
+author: "Chris Kempson (http://chriskempson.com)"
+base00: "1d1f21"
+base01: "282a2e"
+base02: "373b41"
+base03: "969896"
+base04: "b4b7b4"
+base05: "c5c8c6"
+base06: "e0e0e0"
+base07: "ffffff"
+base08: "cc6666"
+base09: "de935f"
+base0A: "f0c674"
+base0B: "b5bd68"
+base0C: "8abeb7"
+base0D: "81a2be"
+base0E: "b294bb"
+base0F: "a3685a"
+`
+
+	testAlacrittyYAML = `# Tomorrow Night
+colors:
+  primary:
+    background: '#1d1f21'
+    foreground: '#c5c8c6'
+  cursor:
+    text: '#1d1f21'
+    cursor: '#ffffff'
+  normal:
+    black:   '#1d1f21'
+    red:     '#cc6666'
+    green:   '#b5bd68'
+    yellow:  '#f0c674'
+    blue:    '#81a2be'
+    magenta: '#b294bb'
+    cyan:    '#8abeb7'
+    white:   '#c5c8c6'
+  bright:
+    black:   '#666666'
+    red:     '#d54e53'
+    green:   '#b9ca4a'
+    yellow:  '#e7c547'
+    blue:    '#7aa6da'
+    magenta: '#c397d8'
+    cyan:    '#70c0b1'
+    white:   '#eaeaea'
+`
+
+	testAlacrittyTOML = `# Tomorrow Night
+[colors.primary]
+background = "#1d1f21"
+foreground = "#c5c8c6"
+
+[colors.cursor]
+text = "#1d1f21"
+cursor = "#ffffff"
+
+[colors.normal]
+black = "0x1d1f21"
+red = "0xcc6666"
+green = "0xb5bd68"
+yellow = "0xf0c674"
+blue = "0x81a2be"
+magenta = "0xb294bb"
+cyan = "0x8abeb7"
+white = "0xc5c8c6"
+
+[colors.bright]
+black = "0x666666"
+red = "0xd54e53"
+green = "0xb9ca4a"
+yellow = "0xe7c547"
+blue = "0x7aa6da"
+magenta = "0xc397d8"
+cyan = "0x70c0b1"
+white = "0xeaeaea"
+`
+
+	testKitty = `# Tomorrow Night
+foreground #c5c8c6
+background #1d1f21
+cursor     #ffffff
+
+color0  #1d1f21
+color1  #cc6666
+color2  #b5bd68
+color3  #f0c674
+color4  #81a2be
+color5  #b294bb
+color6  #8abeb7
+color7  #c5c8c6
+color8  #666666
+color9  #d54e53
+color10 #b9ca4a
+color11 #e7c547
+color12 #7aa6da
+color13 #c397d8
+color14 #70c0b1
+color15 #eaeaea
+`
+
+	testXresources = `! Tomorrow Night
+#define t_background #1d1f21
+#define t_foreground #c5c8c6
+
+*.foreground: t_foreground
+*.background: t_background
+*.cursorColor: #ffffff
+
+*.color0:  #1d1f21
+*.color1:  #cc6666
+*.color2:  #b5bd68
+*.color3:  #f0c674
+*.color4:  #81a2be
+*.color5:  #b294bb
+*.color6:  #8abeb7
+*.color7:  #c5c8c6
+*color8:   #666666
+*color9:   #d54e53
+*color10:  #b9ca4a
+*color11:  #e7c547
+*color12:  #7aa6da
+*color13:  #c397d8
+*color14:  #70c0b1
+*color15:  #eaeaea
+`
+)
+
+// testANSIRoles are the roles assigned to the colors of the ANSI fixtures.
+var testANSIRoles = schemeRoles{
+	fg: "#c5c8c6", bg: "#1d1f21", surface: "#666666", muted: "#666666",
+	red: "#cc6666", green: "#b5bd68", yellow: "#f0c674", blue: "#81a2be",
+	magenta: "#b294bb", cyan: "#8abeb7", pink: "#c397d8", orange: "#e7c547",
+}
+
+// testBase16Roles are the roles assigned to the colors of the base16 fixtures.
+var testBase16Roles = schemeRoles{
+	fg: "#c5c8c6", bg: "#1d1f21", surface: "#373b41", muted: "#969896",
+	red: "#cc6666", orange: "#de935f", yellow: "#f0c674", green: "#b5bd68",
+	cyan: "#8abeb7", blue: "#81a2be", magenta: "#b294bb", pink: "#a3685a",
+}
+
+// writeScheme writes the color scheme to a file within a temporary directory.
+func writeScheme(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestParseScheme(t *testing.T) {
+	base24 := testBase16 + `base10: "161719"
+base11: "0e0f10"
+base12: "d54e53"
+base13: "e7c547"
+base14: "b9ca4a"
+base15: "70c0b1"
+base16: "7aa6da"
+base17: "c397d8"
+`
+
+	tests := []struct {
+		name, file, data string
+		roles            schemeRoles
+	}{
+		{"base16", "tomorrow-night.yaml", testBase16, testBase16Roles},
+		{"base24", "tomorrow-night.yaml", base24, testBase16Roles},
+		{"alacritty yaml", "tomorrow-night.yml", testAlacrittyYAML, testANSIRoles},
+		{"alacritty toml", "tomorrow-night.toml", testAlacrittyTOML, testANSIRoles},
+		{"kitty", "tomorrow-night.conf", testKitty, testANSIRoles},
+		{"xresources", "tomorrow-night.Xresources", testXresources, testANSIRoles},
+	}
+
+	for _, test := range tests {
+		palette, err := parseScheme(writeScheme(t, test.file, test.data))
+		if err != nil {
+			t.Errorf("%s: parseScheme: %v", test.name, err)
+			continue
+		}
+
+		if roles := palette.roles(); roles != test.roles {
+			t.Errorf("%s: roles() = %+v, want %+v", test.name, roles, test.roles)
+		}
+	}
+}
+
+func TestParseSchemePartial(t *testing.T) {
+	palette, err := parseScheme(writeScheme(t, "partial.conf", "color1 #cc6666\ncolor12 #7aa6da\n"))
+	if err != nil {
+		t.Fatalf("parseScheme: %v", err)
+	}
+
+	roles := palette.roles()
+	if roles.fg != "default" || roles.bg != "default" || roles.surface != "default" {
+		t.Errorf("fg, bg, surface = %q, %q, %q, want default", roles.fg, roles.bg, roles.surface)
+	}
+	if roles.red != "#cc6666" || roles.blue != "#7aa6da" || roles.green != "default" {
+		t.Errorf("red, blue, green = %q, %q, %q, want #cc6666, #7aa6da, default", roles.red, roles.blue, roles.green)
+	}
+}
+
+func TestParseSchemeInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":      "",
+		"no colors":  "font_size 12\nscheme: \"None\"\n",
+		"bad colors": "foreground #c5c8\ncolor1 red\nbase0G: \"1d1f21\"\n",
+	} {
+		if _, err := parseScheme(writeScheme(t, "invalid.conf", data)); err == nil {
+			t.Errorf("%s: parseScheme: expected an error", name)
+		}
+	}
+
+	if _, err := parseScheme(filepath.Join(t.TempDir(), "missing.conf")); err == nil {
+		t.Error("parseScheme: expected an error for a missing file")
+	}
+}
+
+func TestImportSchemeName(t *testing.T) {
+	path := writeScheme(t, "tomorrow-night.conf", testKitty)
+
+	for _, name := range []string{".", "..", "../other", `dir\name`, "a/b"} {
+		_, err := ImportScheme(path, name, true)
+		if err == nil || !strings.Contains(err.Error(), "Invalid theme name") {
+			t.Errorf("ImportScheme(%q): error %v, want an invalid name error", name, err)
+		}
+	}
+}
+
+func TestSchemeColor(t *testing.T) {
+	tests := []struct {
+		value, color string
+		valid        bool
+	}{
+		{"#1D1F21", "#1d1f21", true},
+		{"0x1d1f21", "#1d1f21", true},
+		{"1d1f21", "#1d1f21", true},
+		{"#fff", "", false},
+		{"#1d1f2g", "", false},
+		{"red", "", false},
+	}
+
+	for _, test := range tests {
+		color, ok := schemeColor(test.value)
+		if color != test.color || ok != test.valid {
+			t.Errorf("schemeColor(%q) = %q, %v, want %q, %v", test.value, color, ok, test.color, test.valid)
+		}
+	}
+}
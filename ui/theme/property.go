@@ -24,6 +24,10 @@ const (
 	ThemeContextStatusBar ThemeContext = "StatusBar"
 	ThemeContextInstances ThemeContext = "Instances"
 	ThemeContextLinks     ThemeContext = "Links"
+	ThemeContextRenderers ThemeContext = "Renderers"
+	ThemeContextCommands  ThemeContext = "Commands"
+	ThemeContextHelp      ThemeContext = "Help"
+	ThemeContextEditor    ThemeContext = "ThemeEditor"
 
 	ThemeContextPlayerInfo   ThemeContext = "PlayerInfo"
 	ThemeContextPlayer       ThemeContext = "Player"
@@ -234,6 +238,7 @@ var ThemeScopes = map[ThemeContext]map[ThemeItem]struct{}{
 		ThemeProgressBar:     struct{}{},
 		ThemeProgressText:    struct{}{},
 		ThemeSelector:        struct{}{},
+		ThemeTagError:        struct{}{},
 		ThemeTitle:           struct{}{},
 		ThemeVideoFPS:        struct{}{},
 		ThemeVideoResolution: struct{}{},
@@ -366,6 +371,50 @@ var ThemeScopes = map[ThemeContext]map[ThemeItem]struct{}{
 		ThemeTotalDuration:      struct{}{},
 		ThemeVideo:              struct{}{},
 	},
+	ThemeContextCommands: {
+		ThemeBackground:      struct{}{},
+		ThemeDescription:     struct{}{},
+		ThemeKeybinding:      struct{}{},
+		ThemeName:            struct{}{},
+		ThemePopupBorder:     struct{}{},
+		ThemePopupBackground: struct{}{},
+		ThemeSelector:        struct{}{},
+		ThemeTitle:           struct{}{},
+	},
+	ThemeContextHelp: {
+		ThemeBackground:      struct{}{},
+		ThemeDescription:     struct{}{},
+		ThemeInputField:      struct{}{},
+		ThemeInputLabel:      struct{}{},
+		ThemeKeybinding:      struct{}{},
+		ThemeName:            struct{}{},
+		ThemePopupBorder:     struct{}{},
+		ThemePopupBackground: struct{}{},
+		ThemeSelector:        struct{}{},
+		ThemeTagError:        struct{}{},
+		ThemeTitle:           struct{}{},
+	},
+	ThemeContextEditor: {
+		ThemeBackground:      struct{}{},
+		ThemeDescription:     struct{}{},
+		ThemeInputField:      struct{}{},
+		ThemeInputLabel:      struct{}{},
+		ThemeName:            struct{}{},
+		ThemePopupBorder:     struct{}{},
+		ThemePopupBackground: struct{}{},
+		ThemeSelector:        struct{}{},
+		ThemeText:            struct{}{},
+		ThemeTitle:           struct{}{},
+	},
+	ThemeContextRenderers: {
+		ThemeBackground:      struct{}{},
+		ThemeName:            struct{}{},
+		ThemePopupBorder:     struct{}{},
+		ThemePopupBackground: struct{}{},
+		ThemeSelector:        struct{}{},
+		ThemeTagChanged:      struct{}{},
+		ThemeTitle:           struct{}{},
+	},
 	ThemeContextSearch: {
 		ThemeAuthor:          struct{}{},
 		ThemeBackground:      struct{}{},
@@ -2,6 +2,7 @@ package theme
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/darkhz/tview"
@@ -87,7 +88,8 @@ Documentation:
     - Every attribute definition must be separated by a comma(,)
 
     The available attributes are: bold, dim, italic, reverse and underline.
-    For the foreground and background colors, any color/CSS name can be specified.
+    For the foreground and background colors, any color/CSS name, a hex color (#rrggbb),
+    or a color from the 256-color palette (color0 to color255) can be specified.
 
     For example:
     {
@@ -195,6 +197,7 @@ Documentation:
     PopupBackground: bg:black
     ProgressBar: attr:bold; fg:white
     ProgressText: attr:bold; fg:white
+    TagError: attr:bold; bg:red; fg:white
     VideoFPS: attr:bold; fg:yellow
     VideoResolution: attr:bold; fg:green
   }
@@ -311,6 +314,93 @@ Documentation:
 }
 `
 
+// ThemeAttributes lists the attributes which can be set for a theme item.
+var ThemeAttributes = []string{"bold", "underline", "italic", "blink", "dim"}
+
+// SplitThemeValue returns the attributes, the foreground color
+// and the background color from the provided theme parameter.
+func SplitThemeValue(value string) ([]string, string, string) {
+	var attrs []string
+	var fg, bg string
+
+	for _, s := range strings.Split(value, ";") {
+		property := strings.Split(strings.TrimSpace(s), ":")
+		if len(property) != 2 {
+			continue
+		}
+
+		values := strings.Split(property[1], ",")
+
+		switch strings.TrimSpace(property[0]) {
+		case "attr":
+			for _, v := range values {
+				if v = strings.TrimSpace(v); v != "" {
+					attrs = append(attrs, v)
+				}
+			}
+
+		case "fg":
+			fg = strings.TrimSpace(values[0])
+
+		case "bg":
+			bg = strings.TrimSpace(values[0])
+		}
+	}
+
+	return attrs, fg, bg
+}
+
+// JoinThemeValue returns a theme parameter with the provided
+// attributes, foreground color and background color.
+func JoinThemeValue(attrs []string, fg, bg string) string {
+	var values []string
+
+	if len(attrs) > 0 {
+		values = append(values, "attr:"+strings.Join(attrs, ","))
+	}
+	if fg != "" {
+		values = append(values, "fg:"+fg)
+	}
+	if bg != "" {
+		values = append(values, "bg:"+bg)
+	}
+
+	return strings.Join(values, "; ")
+}
+
+// FormatTheme returns the provided theme parameters in the theme file format.
+func FormatTheme(name string, values map[ThemeContext]map[ThemeItem]string) string {
+	var sb strings.Builder
+
+	contexts := make([]string, 0, len(values))
+	for context, items := range values {
+		if len(items) > 0 {
+			contexts = append(contexts, string(context))
+		}
+	}
+	sort.Strings(contexts)
+
+	fmt.Fprintf(&sb, "# The InvidTUI '%s' theme file\n\n{\n", name)
+
+	for _, context := range contexts {
+		items := make([]string, 0, len(values[ThemeContext(context)]))
+		for item := range values[ThemeContext(context)] {
+			items = append(items, string(item))
+		}
+		sort.Strings(items)
+
+		fmt.Fprintf(&sb, "  %s: {\n", context)
+		for _, item := range items {
+			fmt.Fprintf(&sb, "    %s: %s\n", item, values[ThemeContext(context)][ThemeItem(item)])
+		}
+		fmt.Fprintf(&sb, "  }\n")
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
 // GetThemedRegions appends style tags for each region and returns the text.
 func GetThemedRegions(text string) string {
 	return tview.ReplaceRegionStyles(
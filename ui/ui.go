@@ -1,14 +1,22 @@
 package ui
 
 import (
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/darkhz/invidtui/client"
 	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/hooks"
 	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/notify"
+	"github.com/darkhz/invidtui/scrobbler"
 	"github.com/darkhz/invidtui/ui/app"
 	"github.com/darkhz/invidtui/ui/keybinding"
 	"github.com/darkhz/invidtui/ui/menu"
 	"github.com/darkhz/invidtui/ui/player"
 	"github.com/darkhz/invidtui/ui/popup"
+	"github.com/darkhz/invidtui/ui/remote"
 	"github.com/darkhz/invidtui/ui/view"
 	"github.com/darkhz/invidtui/utils"
 	"github.com/darkhz/tview"
@@ -20,9 +28,17 @@ func SetupUI() {
 	if err := app.Setup(); err != nil {
 		cmd.PrintError("UI: Could not start", err)
 	}
+	if err := remote.StartDaemon(); err != nil {
+		cmd.PrintError("UI: Could not start daemon", err)
+	}
+
+	hooks.SetErrorHandler(app.ShowError)
+	notify.SetErrorHandler(app.ShowError)
+	scrobbler.SetErrorHandler(app.ShowError)
 
 	app.InitMenu(menu.Items)
 	app.SetResizeHandler(Resize)
+	app.SetPaneChangedFunc(view.PaneChanged)
 	app.SetGlobalKeybindings(Keybindings)
 
 	instance := utils.GetHostname(client.Instance())
@@ -31,6 +47,10 @@ func SetupUI() {
 
 	app.ShowInfo(msg, true)
 	go detectPlayerClose()
+	go detectDaemonStop()
+	go view.AutoDownload.Start()
+	go view.Dashboard.WatchFeed()
+	go app.WatchConfig()
 
 	player.ParseQuery()
 	view.Search.ParseQuery()
@@ -38,13 +58,47 @@ func SetupUI() {
 	player.Start()
 	view.SetView(&view.Banner)
 
+	if err := remote.Start(); err != nil {
+		app.ShowError(err)
+	}
+	if err := remote.StartMPRIS(); err != nil {
+		app.ShowError(err)
+	}
+	if err := remote.StartMPD(); err != nil {
+		app.ShowError(err)
+	}
+	if err := remote.StartNowPlaying(); err != nil {
+		app.ShowError(err)
+	}
+	if err := scrobbler.Start(app.UI.Closed); err != nil {
+		app.ShowError(err)
+	}
+	player.AddStateListener(scrobble)
+
 	_, focusedItem := app.UI.Pages.GetFrontPage()
 
 	app.UI.SetRoot(app.UI.Area, true).SetFocus(focusedItem).Run()
 }
 
+// scrobble updates the scrobbler with the player state.
+func scrobble(previous, current player.State) {
+	playback := func(state player.State) scrobbler.Playback {
+		return scrobbler.Playback{
+			VideoID:  state.VideoID,
+			Title:    state.Title,
+			Author:   state.Author,
+			Duration: state.Duration,
+			Playing:  state.Status == player.StatusPlaying,
+			Stopped:  state.Status == player.StatusStopped,
+		}
+	}
+
+	scrobbler.Update(playback(previous), playback(current))
+}
+
 // StopUI stops the application.
 func StopUI(skip ...struct{}) {
+	remote.Stop()
 	app.Stop(skip...)
 	player.Stop()
 }
@@ -79,7 +133,9 @@ func Keybindings(event *tcell.EventKey) *tcell.EventKey {
 		view.Dashboard.EventHandler()
 
 	case keybinding.KeySuspend:
-		app.UI.Suspend = true
+		if !remote.Detach() {
+			app.UI.Suspend = true
+		}
 
 	case keybinding.KeyCancel:
 		client.Cancel()
@@ -100,14 +156,76 @@ func Keybindings(event *tcell.EventKey) *tcell.EventKey {
 	case keybinding.KeyTheme:
 		go popup.ShowThemes()
 
+	case keybinding.KeyThemeEditor:
+		popup.ShowThemeEditor()
+		return nil
+
+	case keybinding.KeyViewBack:
+		view.Back()
+
+	case keybinding.KeyViewForward:
+		view.Forward()
+
+	case keybinding.KeyViewHistory:
+		view.ShowViewHistory()
+
+	case keybinding.KeyCommandPalette:
+		app.ShowCommandPalette()
+		return nil
+
+	case keybinding.KeyHelp:
+		app.ShowHelp()
+		return nil
+
+	case keybinding.KeyLayoutCompactPlayer:
+		app.ToggleCompactPlayer()
+
+	case keybinding.KeyLayoutPlayerPosition:
+		app.TogglePlayerPosition()
+
+	case keybinding.KeyLayoutPaneIncrease:
+		app.ResizeSidePane(app.PaneWidthStep)
+
+	case keybinding.KeyLayoutPaneDecrease:
+		app.ResizeSidePane(-app.PaneWidthStep)
+
+	case keybinding.KeySplitView:
+		view.ToggleSplit()
+
+	case keybinding.KeySplitSwitch:
+		app.SwitchPane()
+
 	case keybinding.KeyQuit:
-		StopUI()
+		if !remote.Detach() {
+			StopUI()
+		}
 	}
 
 Event:
 	return tcell.NewEventKey(event.Key(), event.Rune(), event.Modifiers())
 }
 
+// detectDaemonStop stops the application in daemon mode,
+// if an interrupt or termination signal is received.
+func detectDaemonStop() {
+	if !cmd.IsOptionEnabled("daemon") {
+		return
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case <-app.UI.Closed.Done():
+		return
+
+	case <-signals:
+	}
+
+	StopUI()
+}
+
 // detectPlayerClose detects if the player has exited abruptly.
 func detectPlayerClose() {
 	mp.Player().WaitClosed()
@@ -0,0 +1,454 @@
+package view
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/client"
+	"github.com/darkhz/invidtui/cmd"
+	inv "github.com/darkhz/invidtui/invidious"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/knadh/koanf/v2"
+)
+
+// AutoDownloader describes the subscription auto-download handler.
+type AutoDownloader struct {
+	init     bool
+	interval time.Duration
+	rules    map[string]AutoDownloadRule
+
+	archive     map[string]AutoDownloadEntry
+	archivePath string
+	logger      *log.Logger
+
+	mutex sync.Mutex
+}
+
+// AutoDownloadRule describes the download rule for a channel.
+type AutoDownloadRule struct {
+	Audio       bool
+	Keep        int
+	DeleteAfter int
+}
+
+// AutoDownloadEntry describes an entry in the download archive.
+type AutoDownloadEntry struct {
+	VideoID, ChannelID, File string
+	Downloaded               time.Time
+}
+
+// AutoDownload stores the subscription auto-download properties.
+var AutoDownload AutoDownloader
+
+// GetAutoDownloadHandler returns the auto-download configuration handler.
+func GetAutoDownloadHandler() *AutoDownloader {
+	return &AutoDownload
+}
+
+// Parse parses the auto-download rules from the configuration.
+func (a *AutoDownloader) Parse(k *koanf.Koanf, dir string) error {
+	a.interval = time.Hour
+	a.rules = make(map[string]AutoDownloadRule)
+
+	if !k.Exists("autodownload") {
+		return nil
+	}
+
+	if interval := k.Duration("autodownload.interval"); interval > 0 {
+		a.interval = interval
+	}
+
+	for _, channel := range k.MapKeys("autodownload.channels") {
+		r := k.Cut("autodownload.channels." + channel)
+
+		rule := AutoDownloadRule{
+			Audio:       r.Bool("audio"),
+			Keep:        r.Int("keep"),
+			DeleteAfter: r.Int("delete-after"),
+		}
+		if rule.Keep < 0 || rule.DeleteAfter < 0 {
+			return fmt.Errorf("Config: Invalid autodownload rule for channel %s", channel)
+		}
+		if rule.Keep == 0 {
+			rule.Keep = 3
+		}
+
+		a.rules[channel] = rule
+	}
+
+	return nil
+}
+
+// Generate generates the auto-download configuration.
+func (a *AutoDownloader) Generate(k *koanf.Koanf) (interface{}, error) {
+	adMap := k.Get("autodownload")
+	if adMap == nil {
+		adMap = map[string]interface{}{
+			"interval": "1h",
+			"channels": map[string]interface{}{},
+		}
+	}
+
+	return adMap, nil
+}
+
+// Start evaluates the auto-download rules on startup,
+// and periodically within the configured interval.
+func (a *AutoDownloader) Start() {
+	if a.init || len(a.rules) == 0 {
+		return
+	}
+
+	if err := a.setup(); err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	Downloads.Init()
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		a.evaluate()
+
+		select {
+		case <-app.UI.Closed.Done():
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// setup loads the download archive and opens the log file.
+func (a *AutoDownloader) setup() error {
+	archive, err := cmd.GetPath("downloads/archive")
+	if err != nil {
+		return err
+	}
+
+	logfile, err := cmd.GetPath("downloads/autodownload.log")
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.OpenFile(logfile, os.O_WRONLY|os.O_APPEND, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("AutoDownload: Cannot open log file: %w", err)
+	}
+
+	a.archivePath = archive
+	a.archive = make(map[string]AutoDownloadEntry)
+	a.logger = log.New(fd, "", log.LstdFlags)
+
+	if err := a.loadArchive(); err != nil {
+		return err
+	}
+
+	a.init = true
+
+	return nil
+}
+
+// evaluate checks the latest uploads of each configured channel,
+// downloads any new uploads and removes the expired ones.
+func (a *AutoDownloader) evaluate() {
+	if cmd.GetOptionValue("download-dir") == "" {
+		a.log("No download directory set, skipping evaluation")
+		return
+	}
+
+	uploads := a.uploads()
+
+	for channel, rule := range a.rules {
+		videos, ok := uploads[channel]
+		if !ok {
+			continue
+		}
+		if len(videos) > rule.Keep {
+			videos = videos[:rule.Keep]
+		}
+
+		for _, id := range videos {
+			if a.archived(id) {
+				continue
+			}
+
+			a.download(id, channel, rule)
+		}
+
+		a.prune(channel, rule)
+	}
+}
+
+// uploads returns the latest video IDs for each configured channel.
+// If the current instance is authenticated, the user's feed is checked
+// first, and the channel's video list is only fetched for channels
+// which are not present within the feed.
+func (a *AutoDownloader) uploads() map[string][]string {
+	uploads := make(map[string][]string)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	if client.IsAuthInstance() {
+		feed, err := inv.Feed(1, ctx)
+		if err != nil {
+			a.log("Cannot fetch feed: %s", err)
+		}
+
+		for _, video := range feed.Videos {
+			if _, ok := a.rules[video.AuthorID]; ok && video.Type != "shortVideo" {
+				uploads[video.AuthorID] = append(uploads[video.AuthorID], video.VideoID)
+			}
+		}
+	}
+
+	for channel := range a.rules {
+		if _, ok := uploads[channel]; ok {
+			continue
+		}
+
+		videos, err := inv.ChannelUploads(ctx, channel)
+		if err != nil {
+			a.log("Cannot fetch videos for channel %s: %s", channel, err)
+			continue
+		}
+
+		for _, video := range videos {
+			uploads[channel] = append(uploads[channel], video.VideoID)
+		}
+	}
+
+	return uploads
+}
+
+// download downloads the video according to the channel's rule,
+// and records it in the download archive.
+func (a *AutoDownloader) download(id, channel string, rule AutoDownloadRule) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	video, err := inv.Video(id, ctx)
+	if err != nil {
+		a.log("Cannot fetch video %s: %s", id, err)
+		return
+	}
+	if video.LiveNow {
+		return
+	}
+
+	format, ok := autoDownloadFormat(video, rule.Audio)
+	if !ok {
+		a.log("No suitable format found for video %s", id)
+		return
+	}
+
+	dtype := inv.DownloadMediaAudioVideo
+	if rule.Audio {
+		dtype = inv.DownloadMediaAudio
+	}
+
+	data := DownloadData{
+		id:    id,
+		title: video.Title,
+		video: video,
+
+		dtype:     dtype,
+		format:    format,
+		scheduled: true,
+	}
+	if err := Downloads.TransferVideo(data); err != nil {
+		a.log("Failed to download '%s' (%s): %s", video.Title, id, err)
+		return
+	}
+
+	a.mutex.Lock()
+	a.archive[id] = AutoDownloadEntry{
+		VideoID:    id,
+		ChannelID:  channel,
+		File:       filepath.Join(cmd.GetOptionValue("download-dir"), data.filename()),
+		Downloaded: time.Now(),
+	}
+	a.mutex.Unlock()
+
+	if err := a.saveArchive(); err != nil {
+		a.log("Cannot save archive: %s", err)
+	}
+
+	a.log("Downloaded '%s' (%s) from channel %s", video.Title, id, channel)
+}
+
+// prune removes the downloaded files for a channel which are either
+// older than the most recent downloads to keep, or have expired.
+func (a *AutoDownloader) prune(channel string, rule AutoDownloadRule) {
+	var entries []AutoDownloadEntry
+	var modified bool
+
+	a.mutex.Lock()
+	for _, entry := range a.archive {
+		if entry.ChannelID == channel {
+			entries = append(entries, entry)
+		}
+	}
+	a.mutex.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Downloaded.After(entries[j].Downloaded)
+	})
+
+	expiry := time.Duration(rule.DeleteAfter) * 24 * time.Hour
+
+	for i, entry := range entries {
+		if entry.File == "" {
+			continue
+		}
+
+		if i < rule.Keep && (rule.DeleteAfter == 0 || time.Since(entry.Downloaded) < expiry) {
+			continue
+		}
+
+		if err := inv.RemoveDownloadedMedia(entry.File); err != nil {
+			a.log("Cannot remove %s: %s", entry.File, err)
+			continue
+		}
+
+		a.log("Removed %s", entry.File)
+
+		entry.File = ""
+		modified = true
+
+		a.mutex.Lock()
+		a.archive[entry.VideoID] = entry
+		a.mutex.Unlock()
+	}
+
+	if !modified {
+		return
+	}
+
+	if err := a.saveArchive(); err != nil {
+		a.log("Cannot save archive: %s", err)
+	}
+}
+
+// archived returns whether the video is present in the download archive.
+func (a *AutoDownloader) archived(id string) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	_, ok := a.archive[id]
+
+	return ok
+}
+
+// loadArchive loads the download archive.
+// Each line is of the format: <video ID>\t<channel ID>\t<unix time>\t<file>.
+func (a *AutoDownloader) loadArchive() error {
+	fd, err := os.Open(a.archivePath)
+	if err != nil {
+		return fmt.Errorf("AutoDownload: Cannot open archive: %w", err)
+	}
+	defer fd.Close()
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		values := strings.SplitN(scanner.Text(), "\t", 4)
+		if len(values) != 4 {
+			continue
+		}
+
+		downloaded, err := strconv.ParseInt(values[2], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		a.archive[values[0]] = AutoDownloadEntry{
+			VideoID:    values[0],
+			ChannelID:  values[1],
+			File:       values[3],
+			Downloaded: time.Unix(downloaded, 0),
+		}
+	}
+
+	return scanner.Err()
+}
+
+// saveArchive saves the download archive.
+func (a *AutoDownloader) saveArchive() error {
+	var archive strings.Builder
+
+	a.mutex.Lock()
+	for _, entry := range a.archive {
+		fmt.Fprintf(&archive, "%s\t%s\t%d\t%s\n",
+			entry.VideoID, entry.ChannelID, entry.Downloaded.Unix(), entry.File,
+		)
+	}
+	a.mutex.Unlock()
+
+	return os.WriteFile(a.archivePath, []byte(archive.String()), 0644)
+}
+
+// log writes a message to the auto-download log.
+func (a *AutoDownloader) log(format string, v ...interface{}) {
+	if a.logger == nil {
+		return
+	}
+
+	a.logger.Printf(format, v...)
+}
+
+// autoDownloadFormat returns the format to download the video in.
+// If audio is set, the audio-only format with the highest bitrate is selected,
+// otherwise the combined audio and video format with the highest resolution
+// within the configured video resolution is selected.
+func autoDownloadFormat(video inv.VideoData, audio bool) (inv.VideoFormat, bool) {
+	var selected inv.VideoFormat
+	var found bool
+
+	if audio {
+		for _, format := range video.AdaptiveFormats {
+			if !strings.HasPrefix(format.Type, "audio") || format.Container == "" {
+				continue
+			}
+
+			if !found || format.Bitrate > selected.Bitrate {
+				selected, found = format, true
+			}
+		}
+
+		return selected, found
+	}
+
+	resolution := func(res string) int {
+		height, _ := strconv.Atoi(strings.TrimSuffix(res, "p"))
+		return height
+	}
+
+	maxResolution := resolution(cmd.GetOptionValue("video-res"))
+	for _, format := range video.FormatStreams {
+		height := resolution(format.Resolution)
+		if maxResolution > 0 && height > maxResolution {
+			continue
+		}
+
+		if !found || height > resolution(selected.Resolution) {
+			selected, found = format, true
+		}
+	}
+
+	return selected, found
+}
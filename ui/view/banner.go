@@ -24,8 +24,14 @@ type BannerView struct {
 	init, shown bool
 }
 
-// Banner stores the banner view properties.
-var Banner BannerView
+var (
+	// Banner stores the banner view properties.
+	Banner BannerView
+
+	// paneBanner stores the banner view properties for the split pane,
+	// when the banner view is already shown in the other pane.
+	paneBanner BannerView
+)
 
 // Name returns the name of the banner view.
 func (b *BannerView) Name() string {
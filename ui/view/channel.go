@@ -20,9 +20,9 @@ import (
 
 // ChannelView describes the layout of a channel view.
 type ChannelView struct {
-	init                               bool
-	searchText, currentID, currentType string
-	continuation                       map[string]*ChannelContinuation
+	init                                       bool
+	author, searchText, currentID, currentType string
+	continuation                               map[string]*ChannelContinuation
 
 	infoView InfoView
 	views    *tview.Pages
@@ -50,7 +50,7 @@ type ChannelContinuation struct {
 	continuation string
 }
 
-// Channel stores the channel view properties.
+// Channel is used to open new channel views.
 var Channel ChannelView
 
 // Name returns the name of the channel view.
@@ -140,7 +140,7 @@ func (c *ChannelView) View(pageType string) {
 		return
 	}
 
-	SetView(&Channel)
+	SetView(c)
 	app.SelectTab(pageType)
 	c.currentType = pageType
 
@@ -154,31 +154,28 @@ func (c *ChannelView) View(pageType string) {
 	}
 }
 
-// EventHandler shows the channel view according to the provided page type.
+// EventHandler opens a new channel view for the selected entry, according to
+// the provided page type. If justView is set, the most recently visited channel
+// view is shown instead.
 func (c *ChannelView) EventHandler(pageType string, justView bool) {
 	if justView {
-		c.View(pageType)
+		if channel, ok := recentView(c.Name()).(*ChannelView); ok {
+			channel.View(pageType)
+		}
+
 		return
 	}
 
-	c.Init()
-
 	info, err := app.FocusedTableReference()
 	if err != nil {
 		app.ShowError(err)
 		return
 	}
 
-	c.queueWrite(func() {
-		c.currentID = info.AuthorID
-		for _, i := range c.Tabs().Info {
-			ct := c.tableMap[i.Title]
-			ct.table.Clear()
-			ct.loaded = false
-		}
-	})
+	channel := &ChannelView{currentID: info.AuthorID}
+	channel.Init()
 
-	go c.Load(pageType)
+	go channel.Load(pageType)
 }
 
 // Load loads the channel view according to the page type.
@@ -229,9 +226,10 @@ func (c *ChannelView) Load(pageType string, loadMore ...struct{}) {
 RenderView:
 	app.UI.QueueUpdateDraw(func() {
 		if author != "" {
+			c.author = author
 			c.infoView.Set(tview.Escape(author), tview.Escape(description))
 		}
-		if GetCurrentView() != &Channel || app.GetCurrentTab() != pageType {
+		if GetCurrentView() != View(c) || app.GetCurrentTab() != pageType {
 			c.View(pageType)
 		}
 
@@ -1,12 +1,15 @@
 package view
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/darkhz/invidtui/client"
 	inv "github.com/darkhz/invidtui/invidious"
+	"github.com/darkhz/invidtui/notify"
 	"github.com/darkhz/invidtui/ui/app"
 	"github.com/darkhz/invidtui/ui/keybinding"
 	"github.com/darkhz/invidtui/ui/popup"
@@ -884,6 +887,69 @@ func (d *DashboardView) loadSubscriptions(reload bool) {
 	app.ShowInfo("Subscriptions loaded", false)
 }
 
+// WatchFeed periodically checks the user feed, and sends a
+// notification for each video which was not previously present.
+// The feed is only checked while feed notifications are enabled
+// and the current instance is authenticated.
+func (d *DashboardView) WatchFeed() {
+	var seen map[string]struct{}
+	var instance string
+
+	for {
+		if notify.Enabled(notify.CategoryFeed) && client.IsAuthInstance() {
+			if current := client.Instance(); current != instance {
+				seen, instance = nil, current
+			}
+
+			seen = d.checkFeed(seen)
+		} else {
+			seen, instance = nil, ""
+		}
+
+		timer := time.NewTimer(notify.FeedInterval())
+
+		select {
+		case <-app.UI.Closed.Done():
+			timer.Stop()
+			return
+
+		case <-timer.C:
+		}
+	}
+}
+
+// checkFeed sends a notification for each video within the user feed
+// which is not within the previously seen videos, and returns the videos
+// which are currently within the feed. If seen is nil, no notifications are sent.
+func (d *DashboardView) checkFeed(seen map[string]struct{}) map[string]struct{} {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	feed, err := inv.Feed(1, ctx)
+	if err != nil {
+		return seen
+	}
+
+	current := make(map[string]struct{}, len(feed.Videos))
+
+	for _, video := range feed.Videos {
+		current[video.VideoID] = struct{}{}
+
+		if _, ok := seen[video.VideoID]; ok || seen == nil {
+			continue
+		}
+
+		notify.Send(notify.Notification{
+			Category: notify.CategoryFeed,
+			Title:    "New video from " + video.Author,
+			Body:     video.Title,
+			Icon:     inv.VideoThumbnailURL(inv.VideoData{VideoID: video.VideoID}),
+		})
+	}
+
+	return current
+}
+
 // validateToken validates the provided token
 // in the authentication page.
 func (d *DashboardView) validateToken() {
@@ -2,6 +2,7 @@ package view
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -11,7 +12,9 @@ import (
 	"time"
 
 	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/hooks"
 	inv "github.com/darkhz/invidtui/invidious"
+	"github.com/darkhz/invidtui/notify"
 	"github.com/darkhz/invidtui/ui/app"
 	"github.com/darkhz/invidtui/ui/keybinding"
 	"github.com/darkhz/invidtui/ui/theme"
@@ -26,15 +29,19 @@ type DownloadsView struct {
 	init          bool
 	modal         *app.Modal
 	options, view *tview.Table
+	limiter       *utils.Limiter
 
 	property theme.ThemeProperty
 }
 
 // DownloadProgress describes the layout of a progress indicator.
 type DownloadProgress struct {
+	filename       string
 	desc, progress *tview.TableCell
 	bar            *progressbar.ProgressBar
 	builder        theme.ThemeTextBuilder
+	limiter        *utils.Limiter
+	failed         bool
 
 	cancelFunc context.CancelFunc
 }
@@ -42,7 +49,9 @@ type DownloadProgress struct {
 // DownloadData describes the information for the downloading item.
 type DownloadData struct {
 	id, title, dtype string
+	scheduled        bool
 
+	video  inv.VideoData
 	format inv.VideoFormat
 }
 
@@ -93,6 +102,9 @@ func (d *DownloadsView) Init() bool {
 
 	d.modal = app.NewModal("downloads", "Select Download Option", d.options, 40, 60, d.property)
 
+	rate, _ := utils.ParseByteRate(cmd.GetOptionValue("download-limit"))
+	d.limiter = utils.NewLimiter(rate)
+
 	d.init = true
 
 	return true
@@ -219,33 +231,91 @@ func (d *DownloadsView) LoadOptions(id, title string) {
 }
 
 // TransferVideo starts the download for the selected video.
-func (d *DownloadsView) TransferVideo(id, itag, filename string) {
+func (d *DownloadsView) TransferVideo(data DownloadData) error {
 	var progress DownloadProgress
 
+	filename := data.filename()
+
 	app.ShowInfo("Starting download for video "+tview.Escape(filename), false)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	res, file, err := inv.DownloadParams(ctx, id, itag, filename)
+	progress.renderBar(filename, 0, cancel, true)
+	defer app.ConditionalDraw(func() bool {
+		if !progress.failed {
+			progress.remove()
+		}
+
+		return d.IsPageOpen()
+	})
+
+	if data.scheduled {
+		if err := progress.waitSchedule(ctx); err != nil {
+			app.ShowError(err)
+			return err
+		}
+	}
+
+	res, file, err := inv.DownloadParams(ctx, data.id, data.format.Itag, filename)
 	if err != nil {
+		notifyDownload(filename, err)
 		app.ShowError(err)
-		return
+		return err
 	}
 	defer res.Body.Close()
 	defer file.Close()
 
-	progress.renderBar(filename, res.ContentLength, cancel, true)
-	defer app.ConditionalDraw(func() bool {
-		progress.remove()
+	progress.bar.ChangeMax64(res.ContentLength)
+	progress.bar.Reset()
 
-		return d.IsPageOpen()
-	})
+	reader := utils.NewLimitedReader(ctx, res.Body, d.limiter, progress.limiter)
 
-	_, err = io.Copy(io.MultiWriter(file, progress.bar), res.Body)
+	size, err := io.Copy(io.MultiWriter(file, progress.bar), reader)
+	if err == nil {
+		expected := data.format.ContentLength
+		if expected <= 0 {
+			expected = res.ContentLength
+		}
+
+		err = inv.VerifyDownload(ctx, file.Name(), size, expected)
+	}
 	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			progress.markFailed(err)
+			notifyDownload(filename, err)
+		}
+
 		app.ShowError(err)
+		return err
 	}
+
+	metadata := inv.DownloadMetadata{
+		VideoID:    data.id,
+		Title:      data.title,
+		Author:     data.video.Author,
+		Itag:       data.format.Itag,
+		MediaType:  data.dtype,
+		Container:  data.format.Container,
+		File:       file.Name(),
+		Size:       size,
+		Downloaded: time.Now().Unix(),
+	}
+	if err := inv.WriteDownloadMetadata(metadata); err != nil {
+		app.ShowError(err)
+		return err
+	}
+
+	if cmd.IsOptionEnabled("download-sidecars") {
+		if err := inv.WriteDownloadSidecars(ctx, file.Name(), data.video); err != nil {
+			app.ShowError(err)
+		}
+	}
+
+	hooks.Run(hooks.EventDownloadComplete, metadata.Environ())
+	notifyDownload(filename, nil)
+
+	return nil
 }
 
 // TransferPlaylist starts the download for the selected playlist.
@@ -294,8 +364,7 @@ func (d *DownloadsView) OptionKeybindings(event *tcell.EventKey) *tcell.EventKey
 		cell := d.options.GetCell(row, 0)
 
 		if data, ok := cell.GetReference().(DownloadData); ok {
-			filename := data.title + "." + data.format.Container
-			go d.TransferVideo(data.id, data.format.Itag, filename)
+			go d.TransferVideo(data)
 		}
 
 		fallthrough
@@ -315,9 +384,22 @@ func (d *DownloadsView) Keybindings(event *tcell.EventKey) *tcell.EventKey {
 
 		cell := Downloads.view.GetCell(row, 0)
 		if progress, ok := cell.GetReference().(*DownloadProgress); ok {
+			if progress.failed {
+				progress.remove()
+				break
+			}
+
 			progress.cancelFunc()
 		}
 
+	case keybinding.KeyDownloadLimit:
+		row, _ := Downloads.view.GetSelection()
+
+		cell := Downloads.view.GetCell(row, 0)
+		if progress, ok := cell.GetReference().(*DownloadProgress); ok && !progress.failed {
+			progress.setLimit()
+		}
+
 	case keybinding.KeyClose:
 		CloseView()
 	}
@@ -382,11 +464,17 @@ func (d *DownloadsView) renderOptions(video inv.VideoData) {
 				builder.Format(theme.ThemeAudioChannels, "auch", "%d ch", format.AudioChannels)
 			}
 
+			dtype := inv.DownloadMediaAudioVideo
+			if i != 0 {
+				dtype = mtype[0]
+			}
+
 			data := DownloadData{
 				id:    video.VideoID,
 				title: video.Title,
+				video: video,
 
-				dtype:  "video",
+				dtype:  dtype,
 				format: format,
 			}
 
@@ -409,6 +497,112 @@ func (d *DownloadsView) renderOptions(video inv.VideoData) {
 	}
 }
 
+// markFailed flags the download as failed within the downloads view.
+// The entry is kept until it is removed with the cancel keybinding.
+func (p *DownloadProgress) markFailed(err error) {
+	p.builder.Start(theme.ThemeTagError, "error")
+	fmt.Fprintf(&p.builder, " Failed: %s ", tview.Escape(err.Error()))
+	p.builder.Finish()
+
+	text := p.builder.Get()
+	app.ConditionalDraw(func() bool {
+		p.failed = true
+		p.progress.SetText(text)
+
+		return Downloads.IsPageOpen()
+	})
+}
+
+// setLimit shows an input to set the rate limit for the download.
+func (p *DownloadProgress) setLimit() {
+	dofunc := func(text string) {
+		rate, err := utils.ParseByteRate(text)
+		if err != nil {
+			app.ShowError(fmt.Errorf("View: Downloads: %w", err))
+			return
+		}
+
+		p.limiter.SetRate(rate)
+		if rate == 0 {
+			app.ShowInfo("Removed rate limit for "+tview.Escape(p.filename), false)
+			return
+		}
+
+		app.ShowInfo("Set rate limit to "+text+"/s for "+tview.Escape(p.filename), false)
+	}
+
+	app.UI.Status.SetInput("Rate limit (for example, 500K or 2M, 0 to disable):", 0, true, dofunc, nil)
+}
+
+// waitSchedule waits until the download schedule's time window starts.
+// If no schedule is configured, it returns immediately. Only automatic
+// downloads wait for the schedule, manual downloads start immediately.
+func (p *DownloadProgress) waitSchedule(ctx context.Context) error {
+	start, end, err := utils.ParseTimeWindow(cmd.GetOptionValue("download-schedule"))
+	if err != nil {
+		return nil
+	}
+
+	for {
+		wait := utils.TimeUntilWindow(time.Now(), start, end)
+		if wait == 0 {
+			return nil
+		}
+
+		p.builder.Start(theme.ThemeProgressBar, "progress")
+		fmt.Fprintf(&p.builder, "Scheduled for %s", time.Now().Add(wait).Format("15:04"))
+		p.builder.Finish()
+
+		text := p.builder.Get()
+		app.ConditionalDraw(func() bool {
+			p.progress.SetText(text)
+
+			return Downloads.IsPageOpen()
+		})
+
+		if wait > time.Minute {
+			wait = time.Minute
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+
+		case <-timer.C:
+		}
+	}
+}
+
+// notifyDownload sends a notification when a download completes or fails.
+func notifyDownload(filename string, err error) {
+	notification := notify.Notification{
+		Category: notify.CategoryDownload,
+		Title:    "Download complete",
+		Body:     filename,
+	}
+	if err != nil {
+		notification.Title = "Download failed"
+		notification.Body += ": " + err.Error()
+	}
+
+	notify.Send(notification)
+}
+
+// filename returns the name of the file to download the item into.
+// The format's itag is added to the name, so that the audio and video
+// streams of a video in the same container do not overwrite each other.
+func (d DownloadData) filename() string {
+	name := strings.ReplaceAll(d.title, string(os.PathSeparator), "_")
+	if d.format.Itag != "" {
+		name += ".f" + d.format.Itag
+	}
+
+	return name + "." + d.format.Container
+}
+
 // remove removes the currently downloading item from the downloads view.
 func (p *DownloadProgress) remove() {
 	if Downloads.view == nil {
@@ -471,7 +665,9 @@ func (p *DownloadProgress) renderBar(filename string, clen int64, cancel func(),
 
 	p.bar = progressbar.NewOptions64(clen, options...)
 
+	p.filename = filename
 	p.cancelFunc = cancel
+	p.limiter = utils.NewLimiter(0)
 
 	p.builder = theme.NewTextBuilder(theme.ThemeContextDownloads)
 
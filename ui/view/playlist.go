@@ -17,7 +17,7 @@ import (
 
 // PlaylistView describes the layout of a playlist view.
 type PlaylistView struct {
-	ID string
+	ID, title string
 
 	init, auth, removed bool
 	page                int
@@ -31,7 +31,7 @@ type PlaylistView struct {
 	lock *semaphore.Weighted
 }
 
-// Playlist stores the playlist view properties.
+// Playlist is used to open new playlist views.
 var Playlist PlaylistView
 
 // Name returns the name of the playlist view.
@@ -105,21 +105,20 @@ func (p *PlaylistView) View() {
 		return
 	}
 
-	SetView(&Playlist)
+	SetView(p)
 }
 
-// EventHandler shows the playlist view for the currently selected playlist.
-func (p *PlaylistView) EventHandler(justView, auth bool, loadMore ...struct{}) {
+// EventHandler opens a new playlist view for the currently selected playlist.
+// If justView is set, the most recently visited playlist view is shown instead.
+func (p *PlaylistView) EventHandler(justView, auth bool) {
 	if justView {
-		p.View()
+		if playlist, ok := recentView(p.Name()).(*PlaylistView); ok {
+			playlist.View()
+		}
+
 		return
 	}
 
-	p.Init()
-
-	p.auth = auth
-	p.removed = false
-
 	info, err := app.FocusedTableReference()
 	if err != nil {
 		app.ShowError(err)
@@ -130,7 +129,10 @@ func (p *PlaylistView) EventHandler(justView, auth bool, loadMore ...struct{}) {
 		return
 	}
 
-	go p.Load(info.PlaylistID, loadMore...)
+	playlist := &PlaylistView{auth: auth}
+	playlist.Init()
+
+	go playlist.Load(info.PlaylistID)
 }
 
 // Load loads the playlist.
@@ -163,6 +165,7 @@ func (p *PlaylistView) Load(id string, loadMore ...struct{}) {
 
 	app.UI.QueueUpdateDraw(func() {
 		if loadMore == nil {
+			p.title = result.Title
 			p.infoView.Set(tview.Escape(result.Title), tview.Escape(result.Description))
 			p.View()
 
@@ -191,7 +194,7 @@ func (p *PlaylistView) Keybindings(event *tcell.EventKey) *tcell.EventKey {
 		go p.Load(p.ID, struct{}{})
 
 	case keybinding.KeyPlaylistSave:
-		go Playlist.Save(p.ID, p.auth)
+		go p.Save(p.ID, p.auth)
 
 	case keybinding.KeyClose:
 		CloseView()
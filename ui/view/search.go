@@ -40,7 +40,8 @@ type SearchView struct {
 }
 
 var (
-	// Search stores the search view properties
+	// Search stores the search prompt properties,
+	// and is used to open new search views.
 	Search SearchView
 
 	formParams = map[string]map[string][]string{
@@ -96,13 +97,6 @@ func (s *SearchView) Init() bool {
 	s.currentType = "video"
 	s.tab = s.currentType
 
-	s.table = theme.NewTable(s.property)
-	s.table.SetBorder(false)
-	s.table.SetInputCapture(s.Keybindings)
-	s.table.SetFocusFunc(func() {
-		app.SetContextMenu(keybinding.KeyContextSearch, s.table)
-	})
-
 	s.suggestBox = app.NewModal(
 		"suggestion", "Suggestions", nil, 0, 0,
 		s.property.SetItem(theme.ThemePopupBackground),
@@ -173,42 +167,80 @@ func (d *SearchView) ThemeProperty() theme.ThemeProperty {
 	}
 }
 
-// Start shows the search view and fetches results for
-// the search query.
+// newSearchView returns a new search view for the provided search type, query and parameters.
+func newSearchView(stype, query string, parameters map[string]string) *SearchView {
+	s := &SearchView{
+		init:        true,
+		currentType: stype,
+		savedText:   query,
+		parameters:  make(map[string]string),
+		lock:        semaphore.NewWeighted(1),
+	}
+
+	for k, v := range parameters {
+		s.parameters[k] = v
+	}
+
+	s.property = s.ThemeProperty()
+
+	s.table = theme.NewTable(s.property)
+	s.table.SetBorder(false)
+	s.table.SetInputCapture(s.Keybindings)
+	s.table.SetFocusFunc(func() {
+		app.SetContextMenu(keybinding.KeyContextSearch, s.table)
+	})
+
+	return s
+}
+
+// Start fetches results for the search query, and shows them in a new search view.
+// If the query is empty, more results are fetched for the search view.
 func (s *SearchView) Start(text string) {
 	if text == "" {
+		if s.table == nil {
+			return
+		}
+
 		if !s.lock.TryAcquire(1) {
 			app.ShowInfo("Still loading Search results", false)
 			return
 		}
 		defer s.lock.Release(1)
 
-		text = s.savedText
-		goto StartSearch
-	} else {
-		client.Cancel()
-
-		s.page = 0
-		s.savedText = text
+		s.load()
+		return
 	}
 
-	s.addToHistory(text)
+	s.show(Search.currentType, text, Search.parameters)
+}
 
-	app.UI.QueueUpdateDraw(func() {
-		s.table.Clear()
-		s.table.SetSelectable(false, false)
+// StartType fetches results of the provided search type for the search query,
+// without any search parameters, and shows them in a new search view.
+func (s *SearchView) StartType(stype, text string) {
+	s.show(stype, text, nil)
+}
 
-		s.suggestBox.Exit(false)
-		s.parametersBox.Exit(false)
+// show fetches results for the search query, and shows them in a new search view.
+func (s *SearchView) show(stype, text string, parameters map[string]string) {
+	client.Cancel()
+	Search.addToHistory(text)
+
+	app.UI.QueueUpdateDraw(func() {
+		Search.suggestBox.Exit(false)
+		Search.parametersBox.Exit(false)
 		app.UI.Status.SwitchToPage("messages")
 
 		app.SetPrimaryFocus()
 	})
 
-StartSearch:
+	newSearchView(stype, text, parameters).load()
+}
+
+// load fetches the next page of results for the search view.
+func (s *SearchView) load() {
 	app.ShowInfo("Fetching results", true)
 
-	results, page, err := inv.Search(s.currentType, text, s.parameters, s.page)
+	results, page, err := inv.Search(s.currentType, s.savedText, s.parameters, s.page)
 	if err != nil {
 		app.ShowError(err)
 		return
@@ -220,7 +252,10 @@ StartSearch:
 
 	s.page = page
 	app.UI.QueueUpdateDraw(func() {
-		SetView(&Search)
+		if s.table.GetRowCount() == 0 {
+			SetView(s)
+		}
+
 		s.renderResults(results)
 	})
 
@@ -334,7 +369,7 @@ func (s *SearchView) Keybindings(event *tcell.EventKey) *tcell.EventKey {
 		CloseView()
 
 	case keybinding.KeyQuery:
-		s.Query()
+		Search.Query()
 
 	case keybinding.KeyPlaylist:
 		Playlist.EventHandler(event.Modifiers() == tcell.ModAlt, false)
@@ -395,7 +430,7 @@ func (s *SearchView) inputFunc(e *tcell.EventKey) *tcell.EventKey {
 
 	case keybinding.KeySearchSwitchMode:
 		var tab app.Tab
-		if GetCurrentView() != (&Search) {
+		if _, ok := GetCurrentView().(*SearchView); !ok {
 			tab = s.Tabs()
 			tab.Selected = s.tab
 		}
@@ -749,6 +784,6 @@ func (s *SearchView) renderResults(results []inv.SearchData) {
 	app.SetTableSelector(s.table, rows)
 
 	if Banner.shown && len(results) > 0 {
-		app.UI.Pages.SwitchToPage(Search.Name())
+		app.UI.Pages.SwitchToPage(s.Name())
 	}
 }
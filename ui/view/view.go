@@ -2,6 +2,7 @@ package view
 
 import (
 	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/ui/keybinding"
 	"github.com/darkhz/invidtui/ui/theme"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
@@ -19,58 +20,314 @@ type View interface {
 	ThemeProperty() theme.ThemeProperty
 }
 
-var views []View
+// ViewHistory describes the navigation history of the views.
+type ViewHistory struct {
+	views    []View
+	position int
+}
+
+// MaxViewHistory is the maximum number of views stored in the navigation history.
+const MaxViewHistory = 50
 
-// SetView sets the current view.
+// histories stores the navigation history of each split pane.
+var histories [2]ViewHistory
+
+// SetView sets the current view. If the view is not the current view, it is added to
+// the navigation history after the current position, and any forward history is discarded.
+// If the view is already shown in the other split pane, that pane is focused instead.
 func SetView(viewIface View, noappend ...struct{}) {
+	if focusOtherPane(viewIface) {
+		return
+	}
+
 	if !viewIface.Init() {
 		return
 	}
 
+	if pages := app.UnfocusedPages(); pages != nil &&
+		pages.GetPageItem(viewIface.Name()) == viewIface.Primitive() {
+		pages.RemovePage(viewIface.Name())
+	}
+
+	history := paneHistory()
+
 	app.SetTab(viewIface.Tabs(), viewIface.ThemeProperty().Context)
 	app.UI.Pages.AddAndSwitchToPage(viewIface.Name(), viewIface.Primitive(), true)
 	app.SetPrimaryFocus()
 
-	for _, iface := range views {
-		if iface == viewIface && noappend == nil {
+	if noappend != nil {
+		return
+	}
+	if len(history.views) > 0 {
+		if history.views[history.position] == viewIface {
 			return
 		}
+
+		history.views = history.views[:history.position+1]
 	}
-	if noappend != nil {
+
+	history.views = append(history.views, viewIface)
+	if len(history.views) > MaxViewHistory {
+		history.views = history.views[1:]
+	}
+
+	history.position = len(history.views) - 1
+}
+
+// ToggleSplit splits the page area into two panes, each with its own navigation
+// history, or closes the unfocused pane if the page area is already split.
+func ToggleSplit() {
+	if app.IsSplit() {
+		histories[0], histories[1] = histories[app.ActivePane()], ViewHistory{}
+
+		app.SetSplit(false)
+		app.ShowInfo("Split view closed", false)
+
 		return
 	}
 
-	views = append(views, viewIface)
+	app.SetSplit(true)
+
+	banner := startView()
+	histories[app.ActivePane()] = ViewHistory{views: []View{banner}}
+	SetView(banner, struct{}{})
+
+	app.ShowInfo("Split view opened", false)
+}
+
+// PaneChanged updates the tabs according to the current view
+// of the focused split pane.
+func PaneChanged() {
+	v := GetCurrentView()
+
+	app.SetTab(v.Tabs(), v.ThemeProperty().Context)
 }
 
-// CloseView closes the current view.
+// paneHistory returns the navigation history of the focused split pane.
+func paneHistory() *ViewHistory {
+	return &histories[app.ActivePane()]
+}
+
+// otherPaneView returns the view shown in the unfocused split pane.
+func otherPaneView() View {
+	if !app.IsSplit() {
+		return nil
+	}
+
+	history := histories[1-app.ActivePane()]
+	if len(history.views) == 0 {
+		return nil
+	}
+
+	return history.views[history.position]
+}
+
+// focusOtherPane focuses the unfocused split pane if the view is shown within it,
+// since the view's primitive cannot be attached to both panes at once.
+func focusOtherPane(v View) bool {
+	if v == nil || otherPaneView() != v {
+		return false
+	}
+
+	app.FocusPane(1 - app.ActivePane())
+
+	return true
+}
+
+// startView returns the banner view which is not shown in the unfocused split pane.
+func startView() View {
+	if otherPaneView() == &Banner {
+		return &paneBanner
+	}
+
+	return &Banner
+}
+
+// CloseView closes the current view, and removes it from the navigation history.
 func CloseView() {
-	vlen := len(views)
+	history := paneHistory()
 
-	if !views[vlen-1].Exit() {
+	if !GetCurrentView().Exit() {
 		return
 	}
 
-	if vlen > 1 {
-		vlen--
-		views = views[:vlen]
+	if len(history.views) > 1 {
+		next := history.position + 1
+		if history.position > 0 {
+			next = history.position - 1
+		}
+
+		if otherPaneView() == history.views[next] {
+			history.views[history.position] = startView()
+		} else {
+			history.views = append(history.views[:history.position], history.views[history.position+1:]...)
+			if history.position > 0 {
+				history.position--
+			}
+		}
 	}
 
-	SetView(views[vlen-1], struct{}{})
+	SetView(history.views[history.position], struct{}{})
 
 	app.SetPrimaryFocus()
 }
 
+// Back shows the previous view in the navigation history.
+func Back() {
+	history := paneHistory()
+
+	if history.position == 0 {
+		app.ShowInfo("No previous view", false)
+		return
+	}
+
+	JumpToView(history.position - 1)
+}
+
+// Forward shows the next view in the navigation history.
+func Forward() {
+	history := paneHistory()
+
+	if history.position >= len(history.views)-1 {
+		app.ShowInfo("No next view", false)
+		return
+	}
+
+	JumpToView(history.position + 1)
+}
+
+// JumpToView shows the view at the provided position in the navigation history.
+func JumpToView(position int) {
+	history := paneHistory()
+
+	if position < 0 || position >= len(history.views) || focusOtherPane(history.views[position]) {
+		return
+	}
+
+	history.position = position
+	SetView(history.views[position], struct{}{})
+}
+
 // PreviousView returns the view before the one currently displayed.
 func PreviousView() View {
-	if len(views) < 2 {
+	history := paneHistory()
+
+	if history.position == 0 {
 		return nil
 	}
 
-	return views[len(views)-2]
+	return history.views[history.position-1]
 }
 
 // GetCurrentView returns the current view.
 func GetCurrentView() View {
-	return views[len(views)-1]
+	history := paneHistory()
+
+	return history.views[history.position]
+}
+
+// ShowViewHistory shows a popup with the navigation history,
+// to jump to any view within it.
+func ShowViewHistory() {
+	var historyModal *app.Modal
+
+	history := paneHistory()
+
+	property := theme.ThemeProperty{
+		Context: theme.ThemeContextHistory,
+		Item:    theme.ThemePopupBackground,
+	}
+
+	historyView := theme.NewTable(property)
+	historyView.SetSelectable(true, false)
+	historyView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch keybinding.KeyOperation(event, keybinding.KeyContextCommon) {
+		case keybinding.KeySelect:
+			row, _ := historyView.GetSelection()
+
+			historyModal.Exit(false)
+			JumpToView(len(history.views) - row - 1)
+
+		case keybinding.KeyClose:
+			historyModal.Exit(false)
+		}
+
+		return event
+	})
+	historyView.SetFocusFunc(func() {
+		app.SetContextMenu("", nil)
+	})
+
+	width := 0
+	for i := len(history.views) - 1; i >= 0; i-- {
+		row := len(history.views) - i - 1
+		v := history.views[i]
+
+		name := v.Name()
+		if i == history.position {
+			name += " (Current)"
+		}
+
+		description := viewDescription(v)
+		if w := len(name) + len(description); w > width {
+			width = w
+		}
+
+		historyView.SetCell(row, 0, theme.NewTableCell(
+			theme.ThemeContextHistory,
+			theme.ThemeMediaType,
+			name,
+		).
+			SetSelectable(true),
+		)
+
+		historyView.SetCell(row, 1, theme.NewTableCell(
+			theme.ThemeContextHistory,
+			theme.ThemeVideo,
+			tview.Escape(description),
+		).
+			SetExpansion(1).
+			SetMaxWidth(60),
+		)
+	}
+	if width > 60 {
+		width = 60
+	}
+
+	historyView.Select(len(history.views)-history.position-1, 0)
+
+	historyModal = app.NewModal("view_history", "Navigation History", historyView, len(history.views)+4, width+15, property)
+	historyModal.Show(false)
+}
+
+// recentView returns the view with the provided name, which is
+// closest to the current position in the navigation history.
+func recentView(name string) View {
+	history := paneHistory()
+
+	for distance := 0; distance < len(history.views); distance++ {
+		for _, position := range []int{history.position - distance, history.position + distance} {
+			if position >= 0 && position < len(history.views) && history.views[position].Name() == name {
+				return history.views[position]
+			}
+		}
+	}
+
+	return nil
+}
+
+// viewDescription returns a description of the view's contents.
+func viewDescription(v View) string {
+	switch v := v.(type) {
+	case *ChannelView:
+		return v.author
+
+	case *PlaylistView:
+		return v.title
+
+	case *SearchView:
+		return v.savedText
+	}
+
+	return ""
 }
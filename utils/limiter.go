@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Limiter describes a bandwidth limiter.
+type Limiter struct {
+	rate int64
+	next time.Time
+
+	mutex sync.Mutex
+}
+
+// LimitedReader describes a reader which is throttled by one or more limiters.
+type LimitedReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	limiters []*Limiter
+}
+
+// limitChunkSize is the maximum amount of bytes read at once by the LimitedReader.
+const limitChunkSize = 32 * 1024
+
+// NewLimiter returns a new limiter, which allows 'rate' bytes per second.
+// A rate of zero or less disables the limit.
+func NewLimiter(rate int64) *Limiter {
+	return &Limiter{rate: rate}
+}
+
+// SetRate sets the rate of the limiter in bytes per second.
+func (l *Limiter) SetRate(rate int64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.rate = rate
+	l.next = time.Time{}
+}
+
+// Rate returns the rate of the limiter in bytes per second.
+func (l *Limiter) Rate() int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.rate
+}
+
+// Wait blocks until n bytes can be transferred, or the context is canceled.
+func (l *Limiter) Wait(ctx context.Context, n int) error {
+	l.mutex.Lock()
+
+	if l.rate <= 0 {
+		l.mutex.Unlock()
+		return nil
+	}
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(n) / float64(l.rate) * float64(time.Second)))
+
+	l.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-timer.C:
+	}
+
+	return nil
+}
+
+// NewLimitedReader returns a reader which is throttled by the provided limiters.
+// Any nil limiters are ignored.
+func NewLimitedReader(ctx context.Context, reader io.Reader, limiters ...*Limiter) *LimitedReader {
+	r := &LimitedReader{
+		ctx:    ctx,
+		reader: reader,
+	}
+
+	for _, limiter := range limiters {
+		if limiter != nil {
+			r.limiters = append(r.limiters, limiter)
+		}
+	}
+
+	return r
+}
+
+// Read reads from the underlying reader, and waits for
+// all the limiters to allow the amount of bytes read.
+func (r *LimitedReader) Read(p []byte) (int, error) {
+	if len(p) > limitChunkSize {
+		p = p[:limitChunkSize]
+	}
+
+	n, err := r.reader.Read(p)
+	if n <= 0 {
+		return n, err
+	}
+
+	for _, limiter := range r.limiters {
+		if werr := limiter.Wait(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+
+	return n, err
+}
@@ -307,3 +307,82 @@ func GetHostname(hostURL string) string {
 func GetUnixTimeAfter(years int) int64 {
 	return time.Now().AddDate(years, 0, 0).Unix()
 }
+
+// ParseByteRate parses a rate such as "500K" or "1.5M" and returns
+// the amount of bytes it represents. The 'B' and '/s' suffixes are optional.
+func ParseByteRate(rate string) (int64, error) {
+	var multiplier float64 = 1
+
+	value := strings.ToUpper(strings.TrimSpace(rate))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "/S"), "B")
+
+	if value == "" {
+		return 0, fmt.Errorf("Invalid rate %q", rate)
+	}
+
+	switch value[len(value)-1] {
+	case 'K':
+		multiplier = 1024
+
+	case 'M':
+		multiplier = 1024 * 1024
+
+	case 'G':
+		multiplier = 1024 * 1024 * 1024
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("Invalid rate %q", rate)
+	}
+
+	return int64(amount * multiplier), nil
+}
+
+// ParseTimeWindow parses a time window of the form "HH:MM-HH:MM", and returns
+// the start and end of the window as offsets from midnight.
+func ParseTimeWindow(window string) (time.Duration, time.Duration, error) {
+	var offsets [2]time.Duration
+
+	times := strings.Split(window, "-")
+	if len(times) != 2 {
+		return 0, 0, fmt.Errorf("Invalid time window %q", window)
+	}
+
+	for i, t := range times {
+		parsed, err := time.Parse("15:04", strings.TrimSpace(t))
+		if err != nil {
+			return 0, 0, fmt.Errorf("Invalid time %q in window %q", t, window)
+		}
+
+		offsets[i] = time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute
+	}
+
+	if offsets[0] == offsets[1] {
+		return 0, 0, fmt.Errorf("Time window %q is empty", window)
+	}
+
+	return offsets[0], offsets[1], nil
+}
+
+// TimeUntilWindow returns the duration from the provided time until the start
+// of the time window. If the time is within the window, zero is returned.
+func TimeUntilWindow(now time.Time, start, end time.Duration) time.Duration {
+	year, month, day := now.Date()
+	offset := now.Sub(time.Date(year, month, day, 0, 0, 0, 0, now.Location()))
+
+	if (start < end && offset >= start && offset < end) ||
+		(start > end && (offset >= start || offset < end)) {
+		return 0
+	}
+
+	wait := start - offset
+	if wait < 0 {
+		wait += 24 * time.Hour
+	}
+
+	return wait
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseByteRate(t *testing.T) {
+	tests := []struct {
+		rate  string
+		bytes int64
+		valid bool
+	}{
+		{"500", 500, true},
+		{"500K", 500 * 1024, true},
+		{"500k", 500 * 1024, true},
+		{"1.5M", 1536 * 1024, true},
+		{"2G", 2 * 1024 * 1024 * 1024, true},
+		{"500KB", 500 * 1024, true},
+		{"500KB/s", 500 * 1024, true},
+		{" 1M/s ", 1024 * 1024, true},
+		{"0", 0, true},
+		{"", 0, false},
+		{"K", 0, false},
+		{"B/s", 0, false},
+		{"-1M", 0, false},
+		{"fast", 0, false},
+	}
+
+	for _, test := range tests {
+		bytes, err := ParseByteRate(test.rate)
+		if (err == nil) != test.valid {
+			t.Errorf("ParseByteRate(%q): error %v, valid %v", test.rate, err, test.valid)
+			continue
+		}
+		if bytes != test.bytes {
+			t.Errorf("ParseByteRate(%q) = %d, want %d", test.rate, bytes, test.bytes)
+		}
+	}
+}
+
+func TestTimeUntilWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.January, 1, hour, minute, 0, 0, time.UTC)
+	}
+	offset := func(hour, minute int) time.Duration {
+		return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		start, end time.Duration
+		wait       time.Duration
+	}{
+		{"before window", at(1, 0), offset(2, 0), offset(6, 0), time.Hour},
+		{"at window start", at(2, 0), offset(2, 0), offset(6, 0), 0},
+		{"within window", at(4, 30), offset(2, 0), offset(6, 0), 0},
+		{"at window end", at(6, 0), offset(2, 0), offset(6, 0), offset(20, 0)},
+		{"after window", at(23, 0), offset(2, 0), offset(6, 0), offset(3, 0)},
+		{"overnight before", at(20, 0), offset(23, 0), offset(7, 0), offset(3, 0)},
+		{"overnight late", at(23, 30), offset(23, 0), offset(7, 0), 0},
+		{"overnight early", at(6, 59), offset(23, 0), offset(7, 0), 0},
+		{"overnight after", at(7, 0), offset(23, 0), offset(7, 0), offset(16, 0)},
+	}
+
+	for _, test := range tests {
+		if wait := TimeUntilWindow(test.now, test.start, test.end); wait != test.wait {
+			t.Errorf("%s: TimeUntilWindow() = %v, want %v", test.name, wait, test.wait)
+		}
+	}
+}